@@ -2,44 +2,179 @@ package main
 
 import (
 	"flag"
+	"os"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"rpc-test/load_testing"
+	"rpc-test/load_testing/replay"
+	"rpc-test/load_testing/ws"
 )
 
+// Valid values of the -mode flag.
+const (
+	ModeHTTP = "http"
+	ModeWS   = "ws"
+	ModeBoth = "both"
+)
+
+// stringListFlag implements flag.Value so -providerUrl can be given as a
+// comma-separated list and/or repeated on the command line.
+type stringListFlag []string
+
+func (p *stringListFlag) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *stringListFlag) Set(value string) error {
+	for _, url := range strings.Split(value, ",") {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		*p = append(*p, url)
+	}
+	return nil
+}
+
 type flags struct {
-	providerUrl   string
+	providerUrls  stringListFlag
 	rateLimit     uint
 	totalRequests uint
 	reqPerMethod  uint
+	normFactor    float64
+	detail        string
+	scenario      string
+	mode          string
+	connMode      string
+	metricsAddr   string
+	requestLog    string
+	sampleRate    float64
+	wsChannels    stringListFlag
+	wsAccountKeys stringListFlag
+	wsSignatures  stringListFlag
+	wsConnections uint
+	wsDuration    time.Duration
 }
 
 // Setup flags
 func getFlags() (f flags) {
-	flag.StringVar(&f.providerUrl, "providerUrl", "", "URL for test")
+	flag.Var(&f.providerUrls, "providerUrl", "URL for test; comma-separated or repeated to benchmark several providers at once")
 	flag.UintVar(&f.rateLimit, "rateLimit", 500, "provider rate limit")
 	flag.UintVar(&f.totalRequests, "totalRequests", 100_000, "total test requests")
 	flag.UintVar(&f.reqPerMethod, "reqPerMethod", 1, "repeated tests of each request")
+	flag.Float64Var(&f.normFactor, "normFactor", 1, "normalization factor (0,1] controlling how aggressively the latency histogram tail is compressed")
+	flag.StringVar(&f.detail, "detail", load_testing.DetailShort, "report detail: short|long")
+	flag.StringVar(&f.scenario, "scenario", "", "scenario file describing which RPC methods to benchmark and their fixtures (defaults to getAccountInfo only)")
+	flag.StringVar(&f.mode, "mode", ModeHTTP, "which API to benchmark: http|ws|both")
+	flag.StringVar(&f.connMode, "connMode", load_testing.ConnModeShared, "how http connections are shared: shared|perWorker|perRequest")
+	flag.StringVar(&f.metricsAddr, "metricsAddr", "", "optional address (e.g. :9090) to serve live /metrics/stats, /metrics/log and /metrics/prometheus endpoints on while the run is in progress")
+	flag.StringVar(&f.requestLog, "requestLog", "", "optional path to write a structured NDJSON record of every request, for diffing across runs or replaying with load_testing/replay")
+	flag.Float64Var(&f.sampleRate, "sampleRate", 1, "fraction (0,1] of requests to write to -requestLog; lower this for high-volume runs")
+	flag.Var(&f.wsChannels, "wsChannel", "pubsub channel to subscribe to; comma-separated or repeated (default slotSubscribe)")
+	flag.Var(&f.wsAccountKeys, "wsAccountKey", "account pubkey to use for accountSubscribe; comma-separated or repeated (required if wsChannel includes accountSubscribe)")
+	flag.Var(&f.wsSignatures, "wsSignature", "transaction signature to use for signatureSubscribe; comma-separated or repeated (required if wsChannel includes signatureSubscribe)")
+	flag.UintVar(&f.wsConnections, "wsConnections", 10, "number of concurrent websocket connections")
+	flag.DurationVar(&f.wsDuration, "wsDuration", 30*time.Second, "how long to listen for notifications on each websocket connection")
 	flag.Parse()
 
+	if len(f.wsChannels) == 0 {
+		f.wsChannels = stringListFlag{ws.SlotSubscribe}
+	}
+
 	return
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		runReplay(os.Args[2:])
+		return
+	}
+
 	f := getFlags()
 	err := setupLogger()
 	if err != nil {
 		log.Fatalf("Log setup: %s", err)
 	}
 
-	log.Infof("Start testing %s; rate limit: %d req/sec; total requests: %d; repeated tests of each request: %d", f.providerUrl, f.rateLimit, f.totalRequests, f.reqPerMethod)
-	if f.providerUrl == "" {
+	log.Infof("Start testing %s; rate limit: %d req/sec; total requests: %d; repeated tests of each request: %d", f.providerUrls, f.rateLimit, f.totalRequests, f.reqPerMethod)
+	if len(f.providerUrls) == 0 {
 		log.Error("Empty providerUrl")
 		return
 	}
 
-	load_testing.StartTest(f.providerUrl, f.rateLimit, f.totalRequests, f.reqPerMethod)
+	if f.mode == ModeHTTP || f.mode == ModeBoth {
+		scenario := load_testing.DefaultScenario()
+		if f.scenario != "" {
+			var err error
+			scenario, err = load_testing.LoadScenario(f.scenario)
+			if err != nil {
+				log.Fatalf("Load scenario: %s", err)
+			}
+		}
+
+		load_testing.StartTest(load_testing.Config{
+			ProviderURLs:   f.providerUrls,
+			RateLimit:      f.rateLimit,
+			TotalRequests:  f.totalRequests,
+			ReqPerMethod:   f.reqPerMethod,
+			NormFactor:     f.normFactor,
+			Detail:         f.detail,
+			Scenario:       scenario,
+			ConnMode:       f.connMode,
+			MetricsAddr:    f.metricsAddr,
+			RequestLogPath: f.requestLog,
+			SampleRate:     f.sampleRate,
+		})
+	}
+
+	if f.mode == ModeWS || f.mode == ModeBoth {
+		for _, providerURL := range f.providerUrls {
+			stats := ws.StartTest(ws.Config{
+				URL:           providerURL,
+				Connections:   f.wsConnections,
+				Channels:      f.wsChannels,
+				AccountKeys:   f.wsAccountKeys,
+				SignatureKeys: f.wsSignatures,
+				Duration:      f.wsDuration,
+			})
+			for _, channel := range f.wsChannels {
+				log.Info(stats[channel].Report())
+			}
+		}
+	}
+}
+
+// runReplay implements the "replay" subcommand: rpc-test replay -requestLog
+// path.ndjson [-providerUrl https://...]. With no -providerUrl it reports on
+// the logged timings alone; with one, it re-runs the logged method sequence
+// against that URL instead.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	logPath := fs.String("requestLog", "", "NDJSON request log to replay, as written by -requestLog")
+	targetURL := fs.String("providerUrl", "", "if set, re-run the logged method sequence against this URL instead of only reporting on the logged timings")
+	rateLimit := fs.Uint("rateLimit", 500, "rate limit to use when re-running against -providerUrl")
+	normFactor := fs.Float64("normFactor", 1, "normalization factor (0,1] controlling how aggressively the latency histogram tail is compressed")
+	detail := fs.String("detail", load_testing.DetailShort, "report detail: short|long")
+	_ = fs.Parse(args)
+
+	if *logPath == "" {
+		log.Fatal("replay: -requestLog is required")
+	}
+
+	report, err := replay.Run(replay.Config{
+		LogPath:    *logPath,
+		TargetURL:  *targetURL,
+		RateLimit:  *rateLimit,
+		NormFactor: *normFactor,
+		Detail:     *detail,
+	})
+	if err != nil {
+		log.Fatalf("replay: %s", err)
+	}
+	log.Print(report)
 }
 
 func setupLogger() error {
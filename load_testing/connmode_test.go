@@ -0,0 +1,34 @@
+package load_testing
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewHTTPClientKeepAlives(t *testing.T) {
+	shared := newHTTPClient(false)
+	transport, ok := shared.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("shared client Transport = %T, want *http.Transport", shared.Transport)
+	}
+	if transport.DisableKeepAlives {
+		t.Fatal("newHTTPClient(false).Transport.DisableKeepAlives = true, want false for a shared/perWorker client")
+	}
+
+	perRequest := newHTTPClient(true)
+	transport, ok = perRequest.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("perRequest client Transport = %T, want *http.Transport", perRequest.Transport)
+	}
+	if !transport.DisableKeepAlives {
+		t.Fatal("newHTTPClient(true).Transport.DisableKeepAlives = false, want true so every request opens a fresh connection")
+	}
+}
+
+func TestNewHTTPClientReturnsDistinctTransports(t *testing.T) {
+	a := newHTTPClient(false)
+	b := newHTTPClient(false)
+	if a.Transport == b.Transport {
+		t.Fatal("newHTTPClient() calls share a Transport, want each call to get its own so ConnModePerWorker clients don't share connections")
+	}
+}
@@ -0,0 +1,136 @@
+package load_testing
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// volatileFields are JSON paths (dot-separated) that legitimately differ
+// between providers for an otherwise identical request, e.g. the slot a
+// response was served at. They are stripped before a response is
+// canonicalized and hashed.
+var volatileFields = []string{
+	"result.context.slot",
+}
+
+// canonicalizeResponse strips volatileFields from a raw RPC response body
+// and re-marshals it with sorted map keys, so two semantically equal
+// responses hash identically regardless of field order.
+func canonicalizeResponse(body []byte) ([32]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return [32]byte{}, err
+	}
+
+	for _, path := range volatileFields {
+		stripField(v, strings.Split(path, "."))
+	}
+
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return sha256.Sum256(canonical), nil
+}
+
+// stripField deletes the value at path from v, which must be the
+// map[string]interface{} tree produced by json.Unmarshal.
+func stripField(v interface{}, path []string) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	stripField(m[path[0]], path[1:])
+}
+
+// providerPair is an unordered pair of provider URLs, normalized so
+// {a, b} and {b, a} map to the same key.
+type providerPair struct {
+	A, B string
+}
+
+func newProviderPair(a, b string) providerPair {
+	if a > b {
+		a, b = b, a
+	}
+	return providerPair{A: a, B: b}
+}
+
+// ConsistencyMatrix tracks, for every pair of providers benchmarked
+// together, how often their canonicalized responses to the same logical
+// request diverged.
+type ConsistencyMatrix struct {
+	mu        sync.Mutex
+	compared  map[providerPair]uint64
+	mismatchs map[providerPair]uint64
+}
+
+func NewConsistencyMatrix() *ConsistencyMatrix {
+	return &ConsistencyMatrix{
+		compared:  make(map[providerPair]uint64),
+		mismatchs: make(map[providerPair]uint64),
+	}
+}
+
+// Compare records one round of responses for the same logical request,
+// keyed by provider URL. Providers that errored should be omitted from
+// hashes; only pairs that both succeeded are comparable.
+func (c *ConsistencyMatrix) Compare(hashes map[string][32]byte) {
+	urls := make([]string, 0, len(hashes))
+	for url := range hashes {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < len(urls); i++ {
+		for j := i + 1; j < len(urls); j++ {
+			pair := newProviderPair(urls[i], urls[j])
+			c.compared[pair]++
+			if hashes[urls[i]] != hashes[urls[j]] {
+				c.mismatchs[pair]++
+			}
+		}
+	}
+}
+
+// Report renders the consistency matrix as plain text, one line per
+// provider pair that was ever compared.
+func (c *ConsistencyMatrix) Report() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.compared) == 0 {
+		return ""
+	}
+
+	pairs := make([]providerPair, 0, len(c.compared))
+	for pair := range c.compared {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+
+	var b strings.Builder
+	b.WriteString("== consistency matrix ==\n")
+	for _, pair := range pairs {
+		compared := c.compared[pair]
+		mismatches := c.mismatchs[pair]
+		fmt.Fprintf(&b, "  %s <-> %s: %d/%d responses diverged\n", pair.A, pair.B, mismatches, compared)
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package load_testing
+
+import "testing"
+
+func TestCanonicalizeResponseIgnoresVolatileSlot(t *testing.T) {
+	a, err := canonicalizeResponse([]byte(`{"result":{"context":{"slot":1},"value":"x"}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	b, err := canonicalizeResponse([]byte(`{"result":{"context":{"slot":2},"value":"x"}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	if a != b {
+		t.Fatalf("hashes differ despite only volatile slot differing: %x != %x", a, b)
+	}
+}
+
+func TestCanonicalizeResponseDetectsRealDifference(t *testing.T) {
+	a, err := canonicalizeResponse([]byte(`{"result":{"context":{"slot":1},"value":"x"}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	b, err := canonicalizeResponse([]byte(`{"result":{"context":{"slot":1},"value":"y"}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	if a == b {
+		t.Fatalf("hashes match despite differing value field")
+	}
+}
+
+func TestCanonicalizeResponseFieldOrderIndependent(t *testing.T) {
+	a, err := canonicalizeResponse([]byte(`{"result":{"value":"x","context":{"slot":1}}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	b, err := canonicalizeResponse([]byte(`{"result":{"context":{"slot":1},"value":"x"}}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	if a != b {
+		t.Fatalf("hashes differ despite only key order differing: %x != %x", a, b)
+	}
+}
+
+func TestConsistencyMatrixReport(t *testing.T) {
+	c := NewConsistencyMatrix()
+
+	agree, err := canonicalizeResponse([]byte(`{"result":"x"}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+	disagree, err := canonicalizeResponse([]byte(`{"result":"y"}`))
+	if err != nil {
+		t.Fatalf("canonicalizeResponse: %s", err)
+	}
+
+	c.Compare(map[string][32]byte{"a": agree, "b": agree})
+	c.Compare(map[string][32]byte{"a": agree, "b": disagree})
+
+	report := c.Report()
+	want := "  a <-> b: 1/2 responses diverged\n"
+	if report != "== consistency matrix ==\n"+want {
+		t.Fatalf("Report() = %q, want %q", report, "== consistency matrix ==\n"+want)
+	}
+}
+
+func TestConsistencyMatrixReportEmpty(t *testing.T) {
+	c := NewConsistencyMatrix()
+	if got := c.Report(); got != "" {
+		t.Fatalf("Report() on empty matrix = %q, want empty string", got)
+	}
+}
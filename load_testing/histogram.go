@@ -0,0 +1,204 @@
+package load_testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// histogramBucketWidth is the resolution of each bucket in the body of
+	// the histogram, before tail compression kicks in.
+	histogramBucketWidth = 200 * time.Microsecond
+	// histogramNumBuckets is the number of buckets in the body of the
+	// histogram (excluding the tail bucket).
+	histogramNumBuckets = 5000
+
+	histogramBarWidth = 40
+)
+
+var percentilesToReport = []float64{0.5, 0.9, 0.95, 0.99, 0.999}
+
+// Histogram is a fixed-width bucketed latency distribution that can be
+// recorded into concurrently without locking and without allocating.
+// Observations past the normFactor cutoff are folded into a single tail
+// bucket that still remembers how many observations landed in it, so a
+// long GC pause or a slow outlier request doesn't blow up memory.
+type Histogram struct {
+	bucketWidth  time.Duration
+	cutoffBucket int
+
+	buckets []uint64 // len == cutoffBucket, atomically updated
+	tail    uint64   // atomic: count of observations >= cutoffBucket
+	count   uint64   // atomic: total observations recorded
+	sum     uint64   // atomic: sum of all observations, as time.Duration
+}
+
+// NewHistogram builds a Histogram. normFactor is in (0, 1]: it scales down
+// the usable body of the histogram, folding everything past
+// histogramNumBuckets*histogramBucketWidth*normFactor into the tail bucket.
+// A normFactor of 1 disables tail compression beyond the natural range.
+func NewHistogram(normFactor float64) *Histogram {
+	if normFactor <= 0 || normFactor > 1 {
+		normFactor = 1
+	}
+	cutoffBucket := int(float64(histogramNumBuckets) * normFactor)
+	if cutoffBucket < 1 {
+		cutoffBucket = 1
+	}
+
+	return &Histogram{
+		bucketWidth:  histogramBucketWidth,
+		cutoffBucket: cutoffBucket,
+		buckets:      make([]uint64, cutoffBucket),
+	}
+}
+
+// Record adds a single observation to the histogram. It is safe to call
+// from multiple goroutines and never allocates.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddUint64(&h.sum, uint64(d))
+
+	bucket := int(d / h.bucketWidth)
+	if bucket >= h.cutoffBucket {
+		atomic.AddUint64(&h.tail, 1)
+		return
+	}
+	atomic.AddUint64(&h.buckets[bucket], 1)
+}
+
+// Merge folds other's counts into h. other may still be receiving concurrent
+// Record calls (e.g. MetricsServer snapshotting a live recorder), so
+// every field of other is read with an atomic load, matching how Record
+// writes them.
+func (h *Histogram) Merge(other *Histogram) {
+	for i := range other.buckets {
+		c := atomic.LoadUint64(&other.buckets[i])
+		if c == 0 {
+			continue
+		}
+		atomic.AddUint64(&h.buckets[i], c)
+	}
+	atomic.AddUint64(&h.tail, atomic.LoadUint64(&other.tail))
+	atomic.AddUint64(&h.count, atomic.LoadUint64(&other.count))
+	atomic.AddUint64(&h.sum, atomic.LoadUint64(&other.sum))
+}
+
+// Count returns the total number of recorded observations.
+func (h *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&h.count)
+}
+
+// Mean returns the arithmetic mean of all recorded observations.
+func (h *Histogram) Mean() time.Duration {
+	count := atomic.LoadUint64(&h.count)
+	if count == 0 {
+		return 0
+	}
+	return time.Duration(atomic.LoadUint64(&h.sum) / count)
+}
+
+// Percentile returns the approximate duration at percentile p (0..1) and
+// whether the value fell in the compressed tail bucket, in which case only
+// a lower bound (the cutoff) is known.
+func (h *Histogram) Percentile(p float64) (d time.Duration, inTail bool) {
+	count := atomic.LoadUint64(&h.count)
+	if count == 0 {
+		return 0, false
+	}
+
+	target := uint64(math.Ceil(p * float64(count)))
+	if target == 0 {
+		target = 1
+	}
+
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += atomic.LoadUint64(&h.buckets[i])
+		if cumulative >= target {
+			return time.Duration(i+1) * h.bucketWidth, false
+		}
+	}
+	return time.Duration(h.cutoffBucket) * h.bucketWidth, true
+}
+
+// Summary renders the short percentiles table used by -detail short.
+func (h *Histogram) Summary(label string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: count=%d mean=%s\n", label, h.Count(), h.Mean())
+	for _, p := range percentilesToReport {
+		d, inTail := h.Percentile(p)
+		tailMark := ""
+		if inTail {
+			tailMark = "+"
+		}
+		fmt.Fprintf(&b, "  p%-6s %s%s\n", trimPercentileLabel(p), d, tailMark)
+	}
+	if tail := atomic.LoadUint64(&h.tail); tail > 0 {
+		fmt.Fprintf(&b, "  tail (>%s): %d observations\n", time.Duration(h.cutoffBucket)*h.bucketWidth, tail)
+	}
+	return b.String()
+}
+
+// ASCII renders a hey-style ASCII bar histogram used by -detail long.
+func (h *Histogram) ASCII(label string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s histogram:\n", label)
+
+	const numBars = 20
+	step := h.cutoffBucket / numBars
+	if step < 1 {
+		step = 1
+	}
+
+	counts := make([]uint64, 0, numBars+1)
+	upper := make([]time.Duration, 0, numBars+1)
+	var max uint64
+	for i := 0; i < h.cutoffBucket; i += step {
+		end := i + step
+		if end > h.cutoffBucket {
+			end = h.cutoffBucket
+		}
+		var sum uint64
+		for j := i; j < end; j++ {
+			sum += atomic.LoadUint64(&h.buckets[j])
+		}
+		counts = append(counts, sum)
+		upper = append(upper, time.Duration(end)*h.bucketWidth)
+		if sum > max {
+			max = sum
+		}
+	}
+	if tail := atomic.LoadUint64(&h.tail); tail > 0 {
+		counts = append(counts, tail)
+		upper = append(upper, time.Duration(h.cutoffBucket)*h.bucketWidth)
+		if tail > max {
+			max = tail
+		}
+	}
+
+	for i, c := range counts {
+		bars := 0
+		if max > 0 {
+			bars = int(float64(c) / float64(max) * histogramBarWidth)
+		}
+		fmt.Fprintf(&b, "  %9s [%d]\t|%s\n", upper[i], c, strings.Repeat("■", bars))
+	}
+	return b.String()
+}
+
+func trimPercentileLabel(p float64) string {
+	switch p {
+	case 0.999:
+		return "99.9"
+	default:
+		return fmt.Sprintf("%g", p*100)
+	}
+}
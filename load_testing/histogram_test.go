@@ -0,0 +1,92 @@
+package load_testing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	h := NewHistogram(1)
+	h.Record(50 * time.Microsecond)
+	h.Record(50 * time.Microsecond)
+	h.Record(250 * time.Microsecond)
+
+	if got := h.Count(); got != 3 {
+		t.Fatalf("Count() = %d, want 3", got)
+	}
+
+	if d, inTail := h.Percentile(0.5); d != histogramBucketWidth || inTail {
+		t.Fatalf("Percentile(0.5) = (%s, %v), want (%s, false)", d, inTail, histogramBucketWidth)
+	}
+	if d, inTail := h.Percentile(0.99); d != 2*histogramBucketWidth || inTail {
+		t.Fatalf("Percentile(0.99) = (%s, %v), want (%s, false)", d, inTail, 2*histogramBucketWidth)
+	}
+}
+
+func TestHistogramPercentileEmpty(t *testing.T) {
+	h := NewHistogram(1)
+	if d, inTail := h.Percentile(0.5); d != 0 || inTail {
+		t.Fatalf("Percentile(0.5) on empty histogram = (%s, %v), want (0, false)", d, inTail)
+	}
+}
+
+func TestHistogramTailCompression(t *testing.T) {
+	h := NewHistogram(0.001) // cutoffBucket = 5 -> cutoff at 5*200us = 1ms
+	h.Record(500 * time.Microsecond)
+	h.Record(5 * time.Millisecond)
+
+	d, inTail := h.Percentile(0.99)
+	if !inTail {
+		t.Fatalf("Percentile(0.99) inTail = false, want true")
+	}
+	wantCutoff := time.Duration(h.cutoffBucket) * h.bucketWidth
+	if d != wantCutoff {
+		t.Fatalf("Percentile(0.99) = %s, want cutoff %s", d, wantCutoff)
+	}
+}
+
+func TestHistogramMean(t *testing.T) {
+	h := NewHistogram(1)
+	h.Record(10 * time.Millisecond)
+	h.Record(20 * time.Millisecond)
+
+	if got, want := h.Mean(), 15*time.Millisecond; got != want {
+		t.Fatalf("Mean() = %s, want %s", got, want)
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	a := NewHistogram(1)
+	a.Record(50 * time.Microsecond)
+	a.Record(250 * time.Microsecond)
+
+	b := NewHistogram(1)
+	b.Record(50 * time.Microsecond)
+
+	a.Merge(b)
+
+	if got := a.Count(); got != 3 {
+		t.Fatalf("Count() after merge = %d, want 3", got)
+	}
+	if d, inTail := a.Percentile(0.5); d != histogramBucketWidth || inTail {
+		t.Fatalf("Percentile(0.5) after merge = (%s, %v), want (%s, false)", d, inTail, histogramBucketWidth)
+	}
+}
+
+func TestConnRecorderRecordErrorSplitsColdAndWarm(t *testing.T) {
+	c := NewConnRecorder(1)
+
+	c.RecordError(false)
+	c.RecordError(false)
+	c.RecordError(true)
+
+	if got := c.ErrorCount(); got != 3 {
+		t.Fatalf("ErrorCount() = %d, want 3", got)
+	}
+	if got := c.Cold.ErrorCount(); got != 2 {
+		t.Fatalf("Cold.ErrorCount() = %d, want 2", got)
+	}
+	if got := c.Warm.ErrorCount(); got != 1 {
+		t.Fatalf("Warm.ErrorCount() = %d, want 1", got)
+	}
+}
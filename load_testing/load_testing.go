@@ -11,7 +11,9 @@ import (
 	"net/http"
 	"net/http/httptrace"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
@@ -19,30 +21,35 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// DetailShort and DetailLong are the valid values of the -detail flag.
 const (
-	reqPerMethod = 1
+	DetailShort = "short"
+	DetailLong  = "long"
 )
 
-type BenchmarkResult struct {
-	URL            string
-	Method         string
-	MinTimings     RequestTimings
-	MaxTimings     RequestTimings
-	AverageTimings RequestTimings
-	TotalTimings   RequestTimings
-	TotalTime      time.Duration
-	RequestsCount  int
-	Params         interface{}
-}
+// maxWorkers caps concurrency so -rateLimit can't be used to spin up an
+// unbounded number of worker goroutines (and, historically, an unbounded
+// number of per-worker histograms).
+const maxWorkers = 1000
 
-type AggregateBenchmarkResult struct {
-	URL              string
-	Method           string
-	TotalTime        time.Duration
-	RequestsCount    int
-	AggregateTimings RequestTimings
-	MaxTimings       RequestTimings
-	MinTimings       RequestTimings
+// Valid values of the -connMode flag, controlling how http.Client/Transport
+// instances are shared across workers and requests.
+const (
+	ConnModeShared     = "shared"
+	ConnModePerWorker  = "perWorker"
+	ConnModePerRequest = "perRequest"
+)
+
+// newHTTPClient builds an http.Client with its own, unshared Transport.
+// disableKeepAlives forces every request on the client to open a fresh
+// connection, which is what ConnModePerRequest needs to measure cold
+// handshake timings on every single request.
+func newHTTPClient(disableKeepAlives bool) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: disableKeepAlives,
+		},
+	}
 }
 
 type RequestTimings struct {
@@ -52,27 +59,208 @@ type RequestTimings struct {
 	ServerProcessing time.Duration
 }
 
-func performRequest(client *http.Client, url string, request []byte, limiter *rate.Limiter) (time.Duration, RequestTimings, error) {
+// PhaseHistograms records the end-to-end duration of a request alongside
+// each of its phases, so p50/p90/p95/p99/p99.9 can be computed per-phase at
+// report time instead of only tracking min/max/average.
+type PhaseHistograms struct {
+	Total            *Histogram
+	DNSLookup        *Histogram
+	TCPConnection    *Histogram
+	TLSHandshake     *Histogram
+	ServerProcessing *Histogram
+
+	errorCount uint64 // atomic
+}
+
+func NewPhaseHistograms(normFactor float64) *PhaseHistograms {
+	return &PhaseHistograms{
+		Total:            NewHistogram(normFactor),
+		DNSLookup:        NewHistogram(normFactor),
+		TCPConnection:    NewHistogram(normFactor),
+		TLSHandshake:     NewHistogram(normFactor),
+		ServerProcessing: NewHistogram(normFactor),
+	}
+}
+
+// Record stores one request's timings. It is lock-free and alloc-free, so
+// it's cheap enough to call directly from the hot path of every worker.
+func (p *PhaseHistograms) Record(total time.Duration, timings RequestTimings) {
+	p.Total.Record(total)
+	p.DNSLookup.Record(timings.DNSLookup)
+	p.TCPConnection.Record(timings.TCPConnection)
+	p.TLSHandshake.Record(timings.TLSHandshake)
+	p.ServerProcessing.Record(timings.ServerProcessing)
+}
+
+func (p *PhaseHistograms) RecordError() {
+	atomic.AddUint64(&p.errorCount, 1)
+}
+
+func (p *PhaseHistograms) ErrorCount() uint64 {
+	return atomic.LoadUint64(&p.errorCount)
+}
+
+// Merge folds other into p. It is safe to call while other is still being
+// recorded into, because every read of other goes through an atomic load
+// (see Histogram.Merge).
+func (p *PhaseHistograms) Merge(other *PhaseHistograms) {
+	p.Total.Merge(other.Total)
+	p.DNSLookup.Merge(other.DNSLookup)
+	p.TCPConnection.Merge(other.TCPConnection)
+	p.TLSHandshake.Merge(other.TLSHandshake)
+	p.ServerProcessing.Merge(other.ServerProcessing)
+	atomic.AddUint64(&p.errorCount, other.ErrorCount())
+}
+
+// Report renders the recorded histograms according to detail, which must be
+// DetailShort or DetailLong.
+func (p *PhaseHistograms) Report(label, detail string) string {
+	phases := []struct {
+		name string
+		h    *Histogram
+	}{
+		{"total", p.Total},
+		{"dns", p.DNSLookup},
+		{"tcp", p.TCPConnection},
+		{"tls", p.TLSHandshake},
+		{"server processing", p.ServerProcessing},
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "== %s (requests=%d errors=%d) ==\n", label, p.Total.Count(), p.ErrorCount())
+	for _, ph := range phases {
+		if ph.h.Count() == 0 {
+			continue
+		}
+		if detail == DetailLong {
+			b.WriteString(ph.h.ASCII(ph.name))
+		} else {
+			b.WriteString(ph.h.Summary(ph.name))
+		}
+	}
+	return b.String()
+}
+
+// ConnRecorder splits latency histograms by whether the request reused an
+// existing TCP/TLS connection ("warm") or opened a new one ("cold"),
+// because averaging them together conflates DNS/TCP/TLS cost with
+// steady-state request cost.
+type ConnRecorder struct {
+	Cold *PhaseHistograms
+	Warm *PhaseHistograms
+
+	errorCount uint64 // atomic
+}
+
+func NewConnRecorder(normFactor float64) *ConnRecorder {
+	return &ConnRecorder{
+		Cold: NewPhaseHistograms(normFactor),
+		Warm: NewPhaseHistograms(normFactor),
+	}
+}
+
+func (c *ConnRecorder) Record(total time.Duration, timings RequestTimings, reused bool) {
+	if reused {
+		c.Warm.Record(total, timings)
+		return
+	}
+	c.Cold.Record(total, timings)
+}
+
+// RecordError bumps both the top-level error count and the Cold or Warm
+// sub-histogram's error count, keyed on reused, so a provider that only
+// fails fresh-connection requests shows up in the cold bucket instead of
+// only in the top-level "== label (errors=N) ==" line.
+func (c *ConnRecorder) RecordError(reused bool) {
+	atomic.AddUint64(&c.errorCount, 1)
+	if reused {
+		c.Warm.RecordError()
+		return
+	}
+	c.Cold.RecordError()
+}
+
+func (c *ConnRecorder) ErrorCount() uint64 {
+	return atomic.LoadUint64(&c.errorCount)
+}
+
+// Merge folds other into c. Safe to call while other is still being recorded
+// into concurrently (see PhaseHistograms.Merge).
+func (c *ConnRecorder) Merge(other *ConnRecorder) {
+	c.Cold.Merge(other.Cold)
+	c.Warm.Merge(other.Warm)
+	atomic.AddUint64(&c.errorCount, other.ErrorCount())
+}
+
+func (c *ConnRecorder) Report(label, detail string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "== %s (errors=%d) ==\n", label, c.ErrorCount())
+	b.WriteString(c.Cold.Report(label+" cold (new connection)", detail))
+	b.WriteString(c.Warm.Report(label+" warm (reused connection)", detail))
+	return b.String()
+}
+
+// AggregateBenchmarkResult is the final, merged result for one url+method
+// pair, ready to be rendered.
+type AggregateBenchmarkResult struct {
+	URL        string
+	Method     string
+	TotalTime  time.Duration
+	Histograms *ConnRecorder
+}
+
+// RequestOutcome is the full result of one RPC request. It carries enough
+// detail (status code, byte counts, RPC error code) to emit a RequestLogEntry
+// even when the request failed, not just the duration/timings the histograms
+// need on success.
+type RequestOutcome struct {
+	Duration     time.Duration
+	Timings      RequestTimings
+	Body         []byte
+	Reused       bool
+	StatusCode   int
+	BytesOut     int
+	BytesIn      int
+	RPCErrorCode int
+	Err          error
+
+	// ValidationErr is set by benchmarkMethod when PerformRequest succeeded
+	// (HTTP 200, no RPC error) but method.Validate rejected the response, so
+	// RequestLog.Log can record it as a failure instead of a clean 200/0.
+	ValidationErr error
+}
+
+// PerformRequest sends one JSON-RPC request over client and captures its
+// full timing breakdown via httptrace, alongside status code and byte
+// counts. It is exported so load_testing/replay can re-run a logged request
+// sequence using the exact same instrumentation as a live benchmark run.
+func PerformRequest(client *http.Client, url string, request []byte, limiter *rate.Limiter) RequestOutcome {
 	var (
 		dnsStart, connectStart, tlsStart                            time.Time
 		dnsDuration, connectDuration, serverProcessing, tlsDuration time.Duration
-		timings                                                     RequestTimings
+		reused                                                      bool
 	)
 
+	outcome := RequestOutcome{BytesOut: len(request)}
+
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(request)) //nolint:noctx
 	if err != nil {
-		return 0, timings, err
+		outcome.Err = err
+		return outcome
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// Wait for limiter
-	err = limiter.Wait(context.Background())
-	if err != nil {
-		return 0, timings, err
+	if err := limiter.Wait(context.Background()); err != nil {
+		outcome.Err = err
+		return outcome
 	}
 
 	start := time.Now()
 	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
 		DNSStart: func(_ httptrace.DNSStartInfo) {
 			dnsStart = time.Now()
 		},
@@ -96,117 +284,173 @@ func performRequest(client *http.Client, url string, request []byte, limiter *ra
 		},
 	}
 
+	// fail finalizes outcome with whatever timing data the trace captured up
+	// to the point of failure, instead of the zero value, so a mid-handshake
+	// failure (e.g. a TLS timeout) still shows real dns/tcp/tls_ms in a
+	// -requestLog entry.
+	fail := func(err error) RequestOutcome {
+		outcome.Err = err
+		outcome.Reused = reused
+		outcome.Duration = time.Since(start)
+		outcome.Timings = RequestTimings{
+			DNSLookup:        dnsDuration,
+			TCPConnection:    connectDuration,
+			ServerProcessing: serverProcessing,
+			TLSHandshake:     tlsDuration,
+		}
+		return outcome
+	}
+
 	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 	response, err := client.Do(req)
+	outcome.Reused = reused
 	if err != nil {
-		return 0, timings, err
+		return fail(err)
 	}
 	defer response.Body.Close()
+	outcome.StatusCode = response.StatusCode
 	if serverProcessing < 0 {
-		return 0, timings, fmt.Errorf("invalid calculation of serverProcessing")
+		return fail(fmt.Errorf("invalid calculation of serverProcessing"))
 	}
 
 	// read the response body
 	resp, err := io.ReadAll(response.Body)
 	if err != nil {
-		return 0, timings, err
+		return fail(err)
 	}
+	outcome.BytesIn = len(resp)
 	if response.StatusCode != http.StatusOK {
-		return 0, timings, errors.New(response.Status)
+		return fail(errors.New(response.Status))
 	}
-	requestTime := time.Since(start)
 
 	var r jsonrpc.RPCResponse
-	err = json.Unmarshal(resp, &r)
-	if err != nil {
-		return 0, RequestTimings{}, err
+	if err := json.Unmarshal(resp, &r); err != nil {
+		return fail(err)
 	}
 	if r.Error != nil {
-		return 0, RequestTimings{}, r.Error
+		outcome.RPCErrorCode = r.Error.Code
+		return fail(r.Error)
 	}
 
-	timings = RequestTimings{
+	outcome.Duration = time.Since(start)
+	outcome.Timings = RequestTimings{
 		DNSLookup:        dnsDuration,
 		TCPConnection:    connectDuration,
 		ServerProcessing: serverProcessing,
 		TLSHandshake:     tlsDuration,
 	}
-
-	return requestTime, timings, nil
+	outcome.Body = resp
+	return outcome
 }
 
-func benchmarkMethod(url, method string, params interface{}, client *http.Client, request []byte, limiter *rate.Limiter) BenchmarkResult {
-	var totalTime time.Duration
-	var minTimings, maxTimings, totalTimings, avgTimings RequestTimings
-
-	var performedRequests int
-	for i := 0; i < reqPerMethod; i++ {
-		requestTime, timings, err := performRequest(client, url, request, limiter)
-
-		if err != nil {
-			log.Printf("ERROR: URL: %s, ERR: %s", url, err)
+// benchmarkMethod performs reqPerMethod requests against a single provider
+// and records each one directly into recorder. It never sends results over
+// a channel, so a busy worker never blocks on a consumer. Each response is
+// checked with method.Validate (if set), in addition to the generic "no rpc
+// error" check PerformRequest already does. The canonicalized hash of the
+// last successful response is returned so callers can feed it into a
+// ConsistencyMatrix.
+func benchmarkMethod(url string, method Method, client *http.Client, request []byte, limiter *rate.Limiter, reqPerMethod uint, recorder *ConnRecorder, metrics *MetricsServer, requestLog *RequestLog) (lastHash [32]byte, ok bool) {
+	for i := uint(0); i < reqPerMethod; i++ {
+		if metrics != nil {
+			metrics.BeginRequest()
+		}
+		outcome := PerformRequest(client, url, request, limiter)
+		if metrics != nil {
+			metrics.EndRequest()
+		}
+		if outcome.Err != nil {
+			log.Printf("ERROR: URL: %s, Method: %s, ERR: %s", url, method.Name, outcome.Err)
+			recorder.RecordError(outcome.Reused)
+			if metrics != nil {
+				metrics.Observe(RequestRecord{Timestamp: time.Now(), URL: url, Method: method.Name, Status: "error"})
+			}
+			requestLog.Log(url, method.Name, request, outcome, i)
 			continue
 		}
 
-		if performedRequests == 0 {
-			minTimings = timings
-			maxTimings = timings
-		} else {
-			minTimings.DNSLookup = minDuration(minTimings.DNSLookup, timings.DNSLookup)
-			minTimings.TCPConnection = minDuration(minTimings.TCPConnection, timings.TCPConnection)
-			minTimings.ServerProcessing = minDuration(minTimings.ServerProcessing, timings.ServerProcessing)
-			minTimings.TLSHandshake = minDuration(minTimings.TLSHandshake, timings.TLSHandshake)
-
-			maxTimings.DNSLookup = maxDuration(maxTimings.DNSLookup, timings.DNSLookup)
-			maxTimings.TCPConnection = maxDuration(maxTimings.TCPConnection, timings.TCPConnection)
-			maxTimings.ServerProcessing = maxDuration(maxTimings.ServerProcessing, timings.ServerProcessing)
-			maxTimings.TLSHandshake = maxDuration(maxTimings.TLSHandshake, timings.TLSHandshake)
+		if method.Validate != nil {
+			if err := method.Validate(rpcResult(outcome.Body)); err != nil {
+				log.Printf("ERROR: URL: %s, Method: %s, invalid response: %s", url, method.Name, err)
+				recorder.RecordError(outcome.Reused)
+				if metrics != nil {
+					metrics.Observe(RequestRecord{Timestamp: time.Now(), URL: url, Method: method.Name, Status: "error"})
+				}
+				outcome.Err = err
+				outcome.ValidationErr = err
+				requestLog.Log(url, method.Name, request, outcome, i)
+				continue
+			}
 		}
+		recorder.Record(outcome.Duration, outcome.Timings, outcome.Reused)
+		if metrics != nil {
+			metrics.Observe(RequestRecord{Timestamp: time.Now(), URL: url, Method: method.Name, Status: "ok", DurationMs: float64(outcome.Duration.Microseconds()) / 1000})
+		}
+		requestLog.Log(url, method.Name, request, outcome, i)
 
-		totalTimings.DNSLookup += timings.DNSLookup
-		totalTimings.TCPConnection += timings.TCPConnection
-		totalTimings.ServerProcessing += timings.ServerProcessing
-		totalTimings.TLSHandshake += timings.TLSHandshake
-
-		totalTime += requestTime
-		performedRequests++
-	}
-
-	if performedRequests > 0 {
-		avgTimings.DNSLookup = time.Duration(int(totalTimings.DNSLookup) / performedRequests)
-		avgTimings.TCPConnection = time.Duration(int(totalTimings.TCPConnection) / performedRequests)
-		avgTimings.ServerProcessing = time.Duration(int(totalTimings.ServerProcessing) / performedRequests)
-		avgTimings.TLSHandshake = time.Duration(int(totalTimings.TLSHandshake) / performedRequests)
+		hash, err := canonicalizeResponse(outcome.Body)
+		if err != nil {
+			log.Printf("ERROR: URL: %s, Method: %s, canonicalize response: %s", url, method.Name, err)
+			continue
+		}
+		lastHash, ok = hash, true
 	}
+	return lastHash, ok
+}
 
-	return BenchmarkResult{
-		URL:            url,
-		Method:         method,
-		MinTimings:     minTimings,
-		MaxTimings:     maxTimings,
-		AverageTimings: avgTimings,
-		TotalTimings:   totalTimings,
-		TotalTime:      totalTime,
-		RequestsCount:  performedRequests,
-		Params:         params,
+// rpcResult extracts the "result" field of a raw JSON-RPC response body.
+// Malformed bodies yield an empty RawMessage, which Method.Validate
+// implementations will reject on their own.
+func rpcResult(body []byte) json.RawMessage {
+	var r struct {
+		Result json.RawMessage `json:"result"`
 	}
+	_ = json.Unmarshal(body, &r)
+	return r.Result
 }
 
-func minDuration(a, b time.Duration) time.Duration {
-	if a < b {
-		return a
-	}
-	return b
+// urlMethod keys per-provider, per-method state: recorders and reports are
+// aggregated on this granularity since a scenario can drive many methods at
+// once against many providers.
+type urlMethod struct {
+	URL    string
+	Method string
 }
 
-func maxDuration(a, b time.Duration) time.Duration {
-	if a > b {
-		return a
-	}
-	return b
+// Config bundles StartTest's parameters: the series of requests this ran
+// grew StartTest from a handful of positional arguments to a dozen, several
+// of them adjacent same-typed strings that are easy to transpose at the
+// call site with no compiler help.
+type Config struct {
+	ProviderURLs  []string
+	RateLimit     uint
+	TotalRequests uint
+	ReqPerMethod  uint
+	// NormFactor controls how aggressively the tail of the latency
+	// histograms is compressed (see NewHistogram).
+	NormFactor float64
+	// Detail selects how the final report is rendered: DetailShort or
+	// DetailLong.
+	Detail   string
+	Scenario *Scenario
+	// ConnMode controls how http.Client connections are shared across
+	// workers and requests: ConnModeShared, ConnModePerWorker or
+	// ConnModePerRequest.
+	ConnMode string
+	// MetricsAddr, if non-empty, serves live run state (see MetricsServer)
+	// for the duration of the run.
+	MetricsAddr string
+	// RequestLogPath, if non-empty, streams a sampled, structured record of
+	// every request to an NDJSON file (see RequestLog) for later replay.
+	RequestLogPath string
+	SampleRate     float64
 }
 
-func StartTest(providerURL string, rateLimit, totalRequests uint) {
+// StartTest runs the benchmark against every URL in cfg.ProviderURLs
+// concurrently, sending each of them the exact same stream of requests (as
+// described by cfg.Scenario) so their responses and timings are directly
+// comparable.
+func StartTest(cfg Config) {
 	file, err := os.OpenFile("./test_competitors.log", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o666) //nolint:revive
 	if err != nil {
 		log.Fatal(err)
@@ -214,88 +458,153 @@ func StartTest(providerURL string, rateLimit, totalRequests uint) {
 	defer file.Close()
 	log.SetOutput(file)
 
-	resultsMutex := &sync.Mutex{}
-	results := make(chan BenchmarkResult)
-	client := &http.Client{
-		Transport: &http.Transport{
-			DisableKeepAlives: false, // use persistent connections
-		},
+	providerURLs := cfg.ProviderURLs
+	rateLimit := cfg.RateLimit
+	totalRequests := cfg.TotalRequests
+	reqPerMethod := cfg.ReqPerMethod
+	normFactor := cfg.NormFactor
+	scenario := cfg.Scenario
+	sampleRate := cfg.SampleRate
+
+	detail := cfg.Detail
+	if detail != DetailShort && detail != DetailLong {
+		detail = DetailShort
+	}
+	connMode := cfg.ConnMode
+	if connMode != ConnModePerWorker && connMode != ConnModePerRequest {
+		connMode = ConnModeShared
 	}
 
-	aggregateResults := make(map[string]*AggregateBenchmarkResult)
-	var (
-		wg sync.WaitGroup
-	)
-	for i := 0; i < int(totalRequests); i++ {
-		accountIndex := i % len(AccountKeys)
+	var sharedClient *http.Client
+	if connMode == ConnModeShared {
+		sharedClient = newHTTPClient(false)
+	}
+	// Each provider gets its own limiter so -rateLimit keeps meaning "rate
+	// limit per provider" even when several providers are benchmarked
+	// concurrently, instead of the configured rate being divided across them.
+	limiters := make(map[string]*rate.Limiter, len(providerURLs))
+	for _, url := range providerURLs {
+		limiters[url] = rate.NewLimiter(rate.Limit(rateLimit), 1)
+	}
+
+	numWorkers := int(rateLimit)
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	if numWorkers > int(totalRequests) {
+		numWorkers = int(totalRequests)
+	}
+	if numWorkers > maxWorkers {
+		numWorkers = maxWorkers
+	}
+
+	plan := buildPlan(scenario)
+
+	// One recorder per url+method, shared by every worker: Histogram.Record
+	// is already atomic-safe, so sharding a recorder per worker bought
+	// nothing but histogramNumBuckets-sized allocations multiplied by
+	// numWorkers.
+	recorders := make(map[urlMethod]*ConnRecorder)
+	for _, url := range providerURLs {
+		for _, entry := range scenario.Methods {
+			recorders[urlMethod{URL: url, Method: entry.Method}] = NewConnRecorder(normFactor)
+		}
+	}
+	consistency := NewConsistencyMatrix()
+
+	workerClientsByWorker := make([]*http.Client, numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		workerClient := sharedClient
+		if connMode == ConnModePerWorker {
+			workerClient = newHTTPClient(false)
+		}
+		workerClientsByWorker[w] = workerClient
+	}
+
+	var metrics *MetricsServer
+	if cfg.MetricsAddr != "" {
+		metrics = NewMetricsServer(recorders)
+		metrics.ListenAndServe(cfg.MetricsAddr)
+		defer metrics.Shutdown()
+	}
+
+	requestLog, err := NewRequestLog(cfg.RequestLogPath, sampleRate)
+	if err != nil {
+		log.Errorf("request log: %s", err)
+	}
+	defer requestLog.Close()
+
+	jobs := make(chan int, numWorkers)
+	var wg sync.WaitGroup
+	runStart := time.Now()
+	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
-		go func(url string, accountKeys []string, accountIndex int) {
+		go func(workerClient *http.Client) {
 			defer wg.Done()
-			var k interface{} = accountKeys[accountIndex]
-			params := []interface{}{k, map[string]interface{}{
-				"encoding":   "jsonParsed",
-				"commitment": "finalized",
-			}}
-			reqBody, err := json.Marshal(jsonrpc.NewRequest(GetAccountInfo, params)) //nolint:asasalint
-			if err != nil {
-				log.Errorf("Marshal: %s", err)
-				return
+			for iter := range jobs {
+				methodName := plan[iter%len(plan)]
+				method := Methods[methodName]
+				reqPerMethod := reqPerMethodFor(scenario, methodName, reqPerMethod)
+
+				params := method.BuildParams(iter, &scenario.Fixtures)
+				reqBody, err := json.Marshal(jsonrpc.NewRequest(methodName, params)) //nolint:asasalint
+				if err != nil {
+					log.Errorf("Marshal: %s", err)
+					continue
+				}
+
+				hashes := make(map[string][32]byte, len(providerURLs))
+				var hashesMu sync.Mutex
+				var providerWg sync.WaitGroup
+				for _, url := range providerURLs {
+					providerWg.Add(1)
+					go func(url string) {
+						defer providerWg.Done()
+						client := workerClient
+						if connMode == ConnModePerRequest {
+							client = newHTTPClient(true)
+						}
+						recorder := recorders[urlMethod{URL: url, Method: methodName}]
+						hash, ok := benchmarkMethod(url, method, client, reqBody, limiters[url], reqPerMethod, recorder, metrics, requestLog)
+						if !ok {
+							return
+						}
+						hashesMu.Lock()
+						hashes[url] = hash
+						hashesMu.Unlock()
+					}(url)
+				}
+				providerWg.Wait()
+				consistency.Compare(hashes)
 			}
-			resp := benchmarkMethod(url, GetAccountInfo, params, client, reqBody, rate.NewLimiter(rate.Limit(rateLimit), 1))
-			if resp.RequestsCount != 0 {
-				results <- resp
-			}
-		}(providerURL, AccountKeys, accountIndex)
-	}
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	for result := range results {
-		log.Printf("URL: %s, Method: %s, MinTimings: %+v, MaxTimings: %+v, AverageTimings: %+v, TotalTime: %s, RequestsCount: %d, Params: %+v\n", result.URL, result.Method, result.MinTimings, result.MaxTimings, result.AverageTimings, result.TotalTime, result.RequestsCount, result.Params)
-
-		resultsMutex.Lock()
-		key := fmt.Sprintf("%s_%s", result.URL, result.Method)
-		aggregateResult, ok := aggregateResults[key]
-		if !ok {
-			aggregateResults[key] = &AggregateBenchmarkResult{
-				URL:              result.URL,
-				Method:           result.Method,
-				MinTimings:       result.MinTimings,
-				MaxTimings:       result.MaxTimings,
-				AggregateTimings: result.TotalTimings,
-				TotalTime:        result.TotalTime,
-				RequestsCount:    result.RequestsCount,
-			}
-		} else {
-			aggregateResult.TotalTime += result.TotalTime
-			aggregateResult.RequestsCount += result.RequestsCount
-			aggregateResult.AggregateTimings.DNSLookup += result.TotalTimings.DNSLookup
-			aggregateResult.AggregateTimings.TCPConnection += result.TotalTimings.TCPConnection
-			aggregateResult.AggregateTimings.ServerProcessing += result.TotalTimings.ServerProcessing
-			aggregateResult.AggregateTimings.TLSHandshake += result.TotalTimings.TLSHandshake
-
-			aggregateResult.MinTimings.DNSLookup = minDuration(aggregateResult.MinTimings.DNSLookup, result.MinTimings.DNSLookup)
-			aggregateResult.MinTimings.TCPConnection = minDuration(aggregateResult.MinTimings.TCPConnection, result.MinTimings.TCPConnection)
-			aggregateResult.MinTimings.ServerProcessing = minDuration(aggregateResult.MinTimings.ServerProcessing, result.MinTimings.ServerProcessing)
-			aggregateResult.MinTimings.TLSHandshake = minDuration(aggregateResult.MinTimings.TLSHandshake, result.MinTimings.TLSHandshake)
-
-			aggregateResult.MaxTimings.DNSLookup = maxDuration(aggregateResult.MaxTimings.DNSLookup, result.MaxTimings.DNSLookup)
-			aggregateResult.MaxTimings.TCPConnection = maxDuration(aggregateResult.MaxTimings.TCPConnection, result.MaxTimings.TCPConnection)
-			aggregateResult.MaxTimings.ServerProcessing = maxDuration(aggregateResult.MaxTimings.ServerProcessing, result.MaxTimings.ServerProcessing)
-			aggregateResult.MaxTimings.TLSHandshake = maxDuration(aggregateResult.MaxTimings.TLSHandshake, result.MaxTimings.TLSHandshake)
-		}
-		resultsMutex.Unlock()
+		}(workerClientsByWorker[w])
 	}
 
-	for _, aggregateResult := range aggregateResults {
-		aggregateResult.AggregateTimings.DNSLookup = time.Duration(int(aggregateResult.AggregateTimings.DNSLookup) / aggregateResult.RequestsCount)
-		aggregateResult.AggregateTimings.TCPConnection /= time.Duration(aggregateResult.RequestsCount)
-		aggregateResult.AggregateTimings.ServerProcessing /= time.Duration(aggregateResult.RequestsCount)
-		aggregateResult.AggregateTimings.TLSHandshake /= time.Duration(aggregateResult.RequestsCount)
+	for i := 0; i < int(totalRequests); i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	totalTime := time.Since(runStart)
+
+	var report strings.Builder
+	for _, url := range providerURLs {
+		for _, entry := range scenario.Methods {
+			key := urlMethod{URL: url, Method: entry.Method}
+			aggregate := &AggregateBenchmarkResult{
+				URL:        url,
+				Method:     entry.Method,
+				TotalTime:  totalTime,
+				Histograms: recorders[key],
+			}
 
-		log.Printf("TOTAL METHOD RESULT: URL: %s, Method: %s, MinTimings: %+v, MaxTimings: %+v, AverageTimings: %+v, TotalTime: %s, RequestsCount: %d\n", aggregateResult.URL, aggregateResult.Method, aggregateResult.MinTimings, aggregateResult.MaxTimings, aggregateResult.AggregateTimings, aggregateResult.TotalTime, aggregateResult.RequestsCount)
+			label := fmt.Sprintf("%s_%s", aggregate.URL, aggregate.Method)
+			fmt.Fprintf(&report, "TOTAL METHOD RESULT: URL: %s, Method: %s, TotalTime: %s\n%s", aggregate.URL, aggregate.Method, aggregate.TotalTime, aggregate.Histograms.Report(label, detail))
+		}
+	}
+	if len(providerURLs) > 1 {
+		report.WriteString(consistency.Report())
 	}
+
+	log.Print(report.String())
 }
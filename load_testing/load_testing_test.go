@@ -0,0 +1,84 @@
+package load_testing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func unlimited() *rate.Limiter {
+	return rate.NewLimiter(rate.Inf, 1)
+}
+
+func TestPerformRequestSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"value":1}}`))
+	}))
+	defer server.Close()
+
+	outcome := PerformRequest(server.Client(), server.URL, []byte(`{}`), unlimited())
+	if outcome.Err != nil {
+		t.Fatalf("PerformRequest() err = %s, want nil", outcome.Err)
+	}
+	if outcome.StatusCode != http.StatusOK {
+		t.Fatalf("outcome.StatusCode = %d, want 200", outcome.StatusCode)
+	}
+	if outcome.BytesIn == 0 {
+		t.Fatal("outcome.BytesIn = 0, want the response body length")
+	}
+	if outcome.Duration == 0 {
+		t.Fatal("outcome.Duration = 0, want a non-zero elapsed time")
+	}
+}
+
+func TestPerformRequestRPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid params"}}`))
+	}))
+	defer server.Close()
+
+	outcome := PerformRequest(server.Client(), server.URL, []byte(`{}`), unlimited())
+	if outcome.Err == nil {
+		t.Fatal("PerformRequest() err = nil, want the RPC error")
+	}
+	if outcome.RPCErrorCode != -32602 {
+		t.Fatalf("outcome.RPCErrorCode = %d, want -32602", outcome.RPCErrorCode)
+	}
+	// even on an RPC-level error, the timings the trace captured should be
+	// populated rather than left at the zero value.
+	if outcome.Duration == 0 {
+		t.Fatal("outcome.Duration = 0 on RPC error, want the elapsed time up to failure")
+	}
+}
+
+func TestPerformRequestHTTPStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	outcome := PerformRequest(server.Client(), server.URL, []byte(`{}`), unlimited())
+	if outcome.Err == nil {
+		t.Fatal("PerformRequest() err = nil, want an error for the 500 response")
+	}
+	if outcome.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("outcome.StatusCode = %d, want 500", outcome.StatusCode)
+	}
+	if outcome.Duration == 0 {
+		t.Fatal("outcome.Duration = 0 on HTTP status error, want the elapsed time up to failure")
+	}
+}
+
+func TestPerformRequestDialError(t *testing.T) {
+	outcome := PerformRequest(http.DefaultClient, "http://127.0.0.1:0", []byte(`{}`), unlimited())
+	if outcome.Err == nil {
+		t.Fatal("PerformRequest() err = nil, want a dial error")
+	}
+	if outcome.Duration == 0 {
+		t.Fatal("outcome.Duration = 0 on dial error, want the elapsed time up to failure")
+	}
+}
@@ -0,0 +1,187 @@
+package load_testing
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RPC method names supported by the built-in Method registry.
+const (
+	GetAccountInfo          = "getAccountInfo"
+	GetBalance              = "getBalance"
+	GetSlot                 = "getSlot"
+	GetBlockHeight          = "getBlockHeight"
+	GetLatestBlockhash      = "getLatestBlockhash"
+	GetSignatureStatuses    = "getSignatureStatuses"
+	GetMultipleAccounts     = "getMultipleAccounts"
+	GetProgramAccounts      = "getProgramAccounts"
+	GetTransaction          = "getTransaction"
+	GetTokenAccountsByOwner = "getTokenAccountsByOwner"
+	SimulateTransaction     = "simulateTransaction"
+)
+
+// tokenProgramID is the well-known SPL Token program, used as a fixture for
+// getTokenAccountsByOwner.
+const tokenProgramID = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+
+// Method describes one RPC call the benchmark can drive: how to build its
+// params for a given iteration, and how to sanity-check its response beyond
+// the generic "no rpc error" check every method already gets.
+type Method struct {
+	Name        string
+	BuildParams func(iter int, f *Fixtures) []interface{}
+	Validate    func(result json.RawMessage) error
+}
+
+// Fixtures are pools of real on-chain identifiers that BuildParams draws
+// from so requests exercise realistic data instead of a single hardcoded
+// value.
+type Fixtures struct {
+	AccountKeys  []string `json:"accountKeys" yaml:"accountKeys"`
+	Signatures   []string `json:"signatures" yaml:"signatures"`
+	ProgramIDs   []string `json:"programIDs" yaml:"programIDs"`
+	Transactions []string `json:"transactions" yaml:"transactions"` // base64-encoded, pre-signed, pre-serialized transactions for SimulateTransaction
+}
+
+func (f *Fixtures) accountKey(iter int) string {
+	if len(f.AccountKeys) == 0 {
+		return ""
+	}
+	return f.AccountKeys[iter%len(f.AccountKeys)]
+}
+
+func (f *Fixtures) signature(iter int) string {
+	if len(f.Signatures) == 0 {
+		return ""
+	}
+	return f.Signatures[iter%len(f.Signatures)]
+}
+
+func (f *Fixtures) programID(iter int) string {
+	if len(f.ProgramIDs) == 0 {
+		return ""
+	}
+	return f.ProgramIDs[iter%len(f.ProgramIDs)]
+}
+
+func (f *Fixtures) transaction(iter int) string {
+	if len(f.Transactions) == 0 {
+		return ""
+	}
+	return f.Transactions[iter%len(f.Transactions)]
+}
+
+func validateHasField(name string) func(json.RawMessage) error {
+	return func(result json.RawMessage) error {
+		var v map[string]interface{}
+		if err := json.Unmarshal(result, &v); err != nil {
+			return err
+		}
+		if _, ok := v[name]; !ok {
+			return fmt.Errorf("response missing %q field", name)
+		}
+		return nil
+	}
+}
+
+// Methods is the registry of built-in RPC methods the benchmark can drive,
+// keyed by Method.Name.
+var Methods = map[string]Method{
+	GetAccountInfo: {
+		Name: GetAccountInfo,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{f.accountKey(iter), map[string]interface{}{
+				"encoding":   "jsonParsed",
+				"commitment": "finalized",
+			}}
+		},
+		Validate: validateHasField("value"),
+	},
+	GetBalance: {
+		Name: GetBalance,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{f.accountKey(iter)}
+		},
+		Validate: validateHasField("value"),
+	},
+	GetSlot: {
+		Name: GetSlot,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{map[string]interface{}{"commitment": "finalized"}}
+		},
+	},
+	GetBlockHeight: {
+		Name: GetBlockHeight,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{map[string]interface{}{"commitment": "finalized"}}
+		},
+	},
+	GetLatestBlockhash: {
+		Name: GetLatestBlockhash,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{map[string]interface{}{"commitment": "finalized"}}
+		},
+		Validate: validateHasField("value"),
+	},
+	GetSignatureStatuses: {
+		Name: GetSignatureStatuses,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{
+				[]string{f.signature(iter)},
+				map[string]interface{}{"searchTransactionHistory": true},
+			}
+		},
+		Validate: validateHasField("value"),
+	},
+	GetMultipleAccounts: {
+		Name: GetMultipleAccounts,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{
+				[]string{f.accountKey(iter), f.accountKey(iter + 1)},
+				map[string]interface{}{"encoding": "jsonParsed"},
+			}
+		},
+		Validate: validateHasField("value"),
+	},
+	GetProgramAccounts: {
+		Name: GetProgramAccounts,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{f.programID(iter), map[string]interface{}{
+				"encoding": "jsonParsed",
+			}}
+		},
+	},
+	GetTransaction: {
+		Name: GetTransaction,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{f.signature(iter), map[string]interface{}{
+				"encoding":                       "jsonParsed",
+				"maxSupportedTransactionVersion": 0,
+			}}
+		},
+	},
+	GetTokenAccountsByOwner: {
+		Name: GetTokenAccountsByOwner,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{
+				f.accountKey(iter),
+				map[string]interface{}{"programId": tokenProgramID},
+				map[string]interface{}{"encoding": "jsonParsed"},
+			}
+		},
+		Validate: validateHasField("value"),
+	},
+	// SimulateTransaction takes a base64-encoded, pre-signed, pre-serialized
+	// transaction, not a signature, so it only produces useful requests once
+	// a scenario file supplies Fixtures.Transactions for the target network
+	// (there's no safe built-in default: a real transaction embeds a recent
+	// blockhash that expires within minutes).
+	SimulateTransaction: {
+		Name: SimulateTransaction,
+		BuildParams: func(iter int, f *Fixtures) []interface{} {
+			return []interface{}{f.transaction(iter), map[string]interface{}{
+				"encoding": "base64",
+			}}
+		},
+	},
+}
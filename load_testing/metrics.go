@@ -0,0 +1,216 @@
+package load_testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultLogRingSize = 1000
+
+// RequestRecord is one completed request, as streamed to MetricsServer for
+// /metrics/log and to compute live RPS/error counters.
+type RequestRecord struct {
+	Timestamp  time.Time `json:"ts"`
+	URL        string    `json:"url"`
+	Method     string    `json:"method"`
+	Status     string    `json:"status"` // "ok" or "error"
+	DurationMs float64   `json:"duration_ms"`
+}
+
+// MetricsServer exposes a running benchmark's live state over HTTP so long
+// runs don't go dark until the process exits and the log is flushed. It is
+// safe for concurrent use: counters are atomic and the log ring buffer is
+// guarded by a mutex, so every worker can stream into it from the hot path.
+type MetricsServer struct {
+	startedAt time.Time
+
+	requestCount uint64 // atomic
+	errorCount   uint64 // atomic
+	inFlight     int64  // atomic
+
+	mu       sync.Mutex
+	ring     []RequestRecord
+	ringNext int
+	ringFull bool
+
+	recorders map[urlMethod]*ConnRecorder
+	server    *http.Server
+}
+
+// NewMetricsServer builds a server that reports on recorders, which StartTest
+// keeps updating on the hot path for the duration of the run.
+func NewMetricsServer(recorders map[urlMethod]*ConnRecorder) *MetricsServer {
+	return &MetricsServer{
+		startedAt: time.Now(),
+		ring:      make([]RequestRecord, defaultLogRingSize),
+		recorders: recorders,
+	}
+}
+
+// BeginRequest marks one request as in-flight; callers must call EndRequest
+// exactly once per BeginRequest, typically via defer.
+func (m *MetricsServer) BeginRequest() {
+	atomic.AddInt64(&m.inFlight, 1)
+}
+
+func (m *MetricsServer) EndRequest() {
+	atomic.AddInt64(&m.inFlight, -1)
+}
+
+// Observe appends rec to the log ring buffer and bumps the running counters.
+func (m *MetricsServer) Observe(rec RequestRecord) {
+	atomic.AddUint64(&m.requestCount, 1)
+	if rec.Status != "ok" {
+		atomic.AddUint64(&m.errorCount, 1)
+	}
+
+	m.mu.Lock()
+	m.ring[m.ringNext] = rec
+	m.ringNext++
+	if m.ringNext == len(m.ring) {
+		m.ringNext = 0
+		m.ringFull = true
+	}
+	m.mu.Unlock()
+}
+
+func (m *MetricsServer) tail() []RequestRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.ringFull {
+		out := make([]RequestRecord, m.ringNext)
+		copy(out, m.ring[:m.ringNext])
+		return out
+	}
+
+	out := make([]RequestRecord, len(m.ring))
+	copy(out, m.ring[m.ringNext:])
+	copy(out[len(m.ring)-m.ringNext:], m.ring[:m.ringNext])
+	return out
+}
+
+// statsSnapshot is the JSON shape returned by /metrics/stats.
+type statsSnapshot struct {
+	ElapsedSeconds float64               `json:"elapsed_seconds"`
+	RPS            float64               `json:"rps"`
+	InFlight       int64                 `json:"in_flight"`
+	RequestCount   uint64                `json:"request_count"`
+	ErrorCount     uint64                `json:"error_count"`
+	Methods        map[string]methodStat `json:"methods"`
+}
+
+type methodStat struct {
+	RequestCount uint64  `json:"request_count"`
+	ErrorCount   uint64  `json:"error_count"`
+	P50Ms        float64 `json:"p50_ms"`
+	P90Ms        float64 `json:"p90_ms"`
+	P99Ms        float64 `json:"p99_ms"`
+}
+
+func (m *MetricsServer) snapshot() statsSnapshot {
+	elapsed := time.Since(m.startedAt)
+	requestCount := atomic.LoadUint64(&m.requestCount)
+
+	snapshot := statsSnapshot{
+		ElapsedSeconds: elapsed.Seconds(),
+		InFlight:       atomic.LoadInt64(&m.inFlight),
+		RequestCount:   requestCount,
+		ErrorCount:     atomic.LoadUint64(&m.errorCount),
+		Methods:        make(map[string]methodStat, len(m.recorders)),
+	}
+	if elapsed > 0 {
+		snapshot.RPS = float64(requestCount) / elapsed.Seconds()
+	}
+
+	for key, recorder := range m.recorders {
+		// recorder is still being recorded into concurrently, so merge its
+		// Cold/Warm totals into a scratch histogram rather than mutating it.
+		total := NewHistogram(1)
+		total.Merge(recorder.Cold.Total)
+		total.Merge(recorder.Warm.Total)
+
+		p50, _ := total.Percentile(0.5)
+		p90, _ := total.Percentile(0.9)
+		p99, _ := total.Percentile(0.99)
+		snapshot.Methods[fmt.Sprintf("%s_%s", key.URL, key.Method)] = methodStat{
+			RequestCount: total.Count(),
+			ErrorCount:   recorder.ErrorCount(),
+			P50Ms:        p50.Seconds() * 1000,
+			P90Ms:        p90.Seconds() * 1000,
+			P99Ms:        p99.Seconds() * 1000,
+		}
+	}
+
+	return snapshot
+}
+
+func (m *MetricsServer) handleStats(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(m.snapshot())
+}
+
+func (m *MetricsServer) handleLog(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(m.tail())
+}
+
+func (m *MetricsServer) handlePrometheus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP rpc_test_requests_total Total requests sent, per url and method\n")
+	fmt.Fprintf(w, "# TYPE rpc_test_requests_total counter\n")
+	for key, recorder := range m.recorders {
+		total := NewHistogram(1)
+		total.Merge(recorder.Cold.Total)
+		total.Merge(recorder.Warm.Total)
+
+		fmt.Fprintf(w, "rpc_test_requests_total{url=%q,method=%q} %d\n", key.URL, key.Method, total.Count())
+		fmt.Fprintf(w, "rpc_test_errors_total{url=%q,method=%q} %d\n", key.URL, key.Method, recorder.ErrorCount())
+		for _, p := range percentilesToReport {
+			d, _ := total.Percentile(p)
+			fmt.Fprintf(w, "rpc_test_request_duration_seconds{url=%q,method=%q,quantile=%q} %f\n", key.URL, key.Method, prometheusQuantileLabel(p), d.Seconds())
+		}
+	}
+}
+
+// prometheusQuantileLabel renders p as the fraction Prometheus summary
+// metrics expect (e.g. 0.99, 0.999), unlike trimPercentileLabel which
+// renders a percentage for the human-readable reports.
+func prometheusQuantileLabel(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// ListenAndServe starts the metrics HTTP server in the background. It
+// returns immediately; call Shutdown when the benchmark run finishes.
+func (m *MetricsServer) ListenAndServe(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/stats", m.handleStats)
+	mux.HandleFunc("/metrics/log", m.handleLog)
+	mux.HandleFunc("/metrics/prometheus", m.handlePrometheus)
+
+	m.server = &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := m.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("metrics server: %s", err)
+		}
+	}()
+}
+
+// Shutdown stops the metrics HTTP server.
+func (m *MetricsServer) Shutdown() {
+	if m.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = m.server.Shutdown(ctx)
+}
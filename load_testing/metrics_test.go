@@ -0,0 +1,92 @@
+package load_testing
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsServerTailBeforeWrap(t *testing.T) {
+	m := NewMetricsServer(nil)
+	m.ring = make([]RequestRecord, 4)
+
+	m.Observe(RequestRecord{Method: "a"})
+	m.Observe(RequestRecord{Method: "b"})
+
+	got := m.tail()
+	if len(got) != 2 || got[0].Method != "a" || got[1].Method != "b" {
+		t.Fatalf("tail() = %+v, want [a b]", got)
+	}
+}
+
+func TestMetricsServerTailWrapsInOrder(t *testing.T) {
+	m := NewMetricsServer(nil)
+	m.ring = make([]RequestRecord, 3)
+
+	for _, method := range []string{"a", "b", "c", "d", "e"} {
+		m.Observe(RequestRecord{Method: method})
+	}
+
+	got := m.tail()
+	if len(got) != 3 {
+		t.Fatalf("tail() len = %d, want 3", len(got))
+	}
+	want := []string{"c", "d", "e"}
+	for i, method := range want {
+		if got[i].Method != method {
+			t.Fatalf("tail() = %+v, want %v", got, want)
+		}
+	}
+}
+
+func TestMetricsServerSnapshotAggregatesColdAndWarm(t *testing.T) {
+	key := urlMethod{URL: "https://provider", Method: GetAccountInfo}
+
+	recorder := NewConnRecorder(1)
+	recorder.Record(10*time.Millisecond, RequestTimings{}, false)
+	recorder.Record(20*time.Millisecond, RequestTimings{}, true)
+	recorder.RecordError(false)
+
+	m := NewMetricsServer(map[urlMethod]*ConnRecorder{
+		key: recorder,
+	})
+
+	snapshot := m.snapshot()
+	stat, ok := snapshot.Methods["https://provider_getAccountInfo"]
+	if !ok {
+		t.Fatalf("snapshot.Methods = %+v, want an entry for the key", snapshot.Methods)
+	}
+	if stat.RequestCount != 2 {
+		t.Fatalf("stat.RequestCount = %d, want 2", stat.RequestCount)
+	}
+	if stat.ErrorCount != 1 {
+		t.Fatalf("stat.ErrorCount = %d, want 1", stat.ErrorCount)
+	}
+}
+
+func TestHandlePrometheusUsesFractionalQuantileLabels(t *testing.T) {
+	key := urlMethod{URL: "https://provider", Method: GetAccountInfo}
+
+	recorder := NewConnRecorder(1)
+	recorder.Record(10*time.Millisecond, RequestTimings{}, false)
+	recorder.RecordError(false)
+
+	m := NewMetricsServer(map[urlMethod]*ConnRecorder{
+		key: recorder,
+	})
+
+	req := httptest.NewRequest("GET", "/metrics/prometheus", nil)
+	rec := httptest.NewRecorder()
+	m.handlePrometheus(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{`quantile="0.5"`, `quantile="0.9"`, `quantile="0.95"`, `quantile="0.99"`, `quantile="0.999"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("handlePrometheus() body missing %s, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `quantile="50"`) || strings.Contains(body, `quantile="99.9"`) {
+		t.Fatalf("handlePrometheus() body used percentage-style quantile label, got:\n%s", body)
+	}
+}
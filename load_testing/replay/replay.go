@@ -0,0 +1,176 @@
+// Package replay reads a -requestLog NDJSON file produced by load_testing
+// and either re-runs the logged method sequence against a new provider URL,
+// or renders a post-hoc histogram/percentile report straight from the
+// logged timings, without sending a single request.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"golang.org/x/time/rate"
+
+	"rpc-test/load_testing"
+)
+
+// Config describes one replay run.
+type Config struct {
+	LogPath    string
+	TargetURL  string // if empty, Run only reports on the logged timings
+	RateLimit  uint
+	NormFactor float64
+	Detail     string
+}
+
+// Run reads cfg.LogPath and returns a rendered report. If cfg.TargetURL is
+// empty, the report is built purely from the logged timings. Otherwise Run
+// re-sends one request per logged entry, in order, against cfg.TargetURL and
+// reports on the fresh timings instead.
+//
+// Requests are rebuilt from the Methods registry's default param builders,
+// not replayed byte-for-byte: the log only stores a params hash, not the raw
+// params. If the original run used a -scenario file with custom fixtures,
+// the rebuilt params will generally differ from what was actually logged;
+// rerun compares each rebuilt request's hash against the logged ParamsHash
+// and reports a prominent mismatch count up front so that drift isn't
+// mistaken for a faithful re-run.
+func Run(cfg Config) (string, error) {
+	entries, err := readLog(cfg.LogPath)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no entries in %s", cfg.LogPath)
+	}
+
+	if cfg.TargetURL == "" {
+		return reportFromLog(entries, cfg.Detail), nil
+	}
+	return rerun(entries, cfg)
+}
+
+func readLog(path string) ([]load_testing.RequestLogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open request log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []load_testing.RequestLogEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry load_testing.RequestLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("parse request log line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// reportFromLog builds a percentile report directly from the logged
+// per-request timings, keyed by method, so two runs' logs can be diffed
+// without either provider still being reachable.
+func reportFromLog(entries []load_testing.RequestLogEntry, detail string) string {
+	if detail != load_testing.DetailLong {
+		detail = load_testing.DetailShort
+	}
+
+	histograms := make(map[string]*load_testing.Histogram)
+	errorCounts := make(map[string]uint64)
+	order := make([]string, 0)
+	for _, entry := range entries {
+		h, ok := histograms[entry.Method]
+		if !ok {
+			h = load_testing.NewHistogram(1)
+			histograms[entry.Method] = h
+			order = append(order, entry.Method)
+		}
+		if entry.HTTPStatus != http.StatusOK || entry.RPCErrorCode != 0 || entry.ValidationError != "" {
+			errorCounts[entry.Method]++
+			continue
+		}
+		h.Record(time.Duration(entry.TotalMs * float64(time.Millisecond)))
+	}
+
+	var b strings.Builder
+	for _, method := range order {
+		h := histograms[method]
+		fmt.Fprintf(&b, "== %s (requests=%d errors=%d, from log) ==\n", method, h.Count(), errorCounts[method])
+		if detail == load_testing.DetailLong {
+			b.WriteString(h.ASCII("total"))
+		} else {
+			b.WriteString(h.Summary("total"))
+		}
+	}
+	return b.String()
+}
+
+// rerun replays the logged method sequence, in order, against cfg.TargetURL.
+func rerun(entries []load_testing.RequestLogEntry, cfg Config) (string, error) {
+	client := &http.Client{}
+	rateLimit := cfg.RateLimit
+	if rateLimit == 0 {
+		rateLimit = 500
+	}
+	limiter := rate.NewLimiter(rate.Limit(rateLimit), 1)
+	fixtures := load_testing.DefaultFixtures
+
+	recorders := make(map[string]*load_testing.ConnRecorder)
+	order := make([]string, 0)
+	var paramsMismatch int
+	for iter, entry := range entries {
+		method, ok := load_testing.Methods[entry.Method]
+		if !ok {
+			continue
+		}
+		recorder, ok := recorders[entry.Method]
+		if !ok {
+			recorder = load_testing.NewConnRecorder(cfg.NormFactor)
+			recorders[entry.Method] = recorder
+			order = append(order, entry.Method)
+		}
+
+		params := method.BuildParams(iter, &fixtures)
+		reqBody, err := json.Marshal(jsonrpc.NewRequest(entry.Method, params)) //nolint:asasalint
+		if err != nil {
+			return "", fmt.Errorf("marshal replay request: %w", err)
+		}
+		if load_testing.ParamsHash(reqBody) != entry.ParamsHash {
+			paramsMismatch++
+		}
+
+		outcome := load_testing.PerformRequest(client, cfg.TargetURL, reqBody, limiter)
+		if outcome.Err != nil {
+			recorder.RecordError(outcome.Reused)
+			continue
+		}
+		recorder.Record(outcome.Duration, outcome.Timings, outcome.Reused)
+	}
+
+	detail := cfg.Detail
+	if detail != load_testing.DetailLong {
+		detail = load_testing.DetailShort
+	}
+	var b strings.Builder
+	if paramsMismatch > 0 {
+		fmt.Fprintf(&b, "WARNING: %d/%d replayed requests used params that don't match the original run's logged ParamsHash "+
+			"(the original run likely used a -scenario file with custom fixtures, which replay can't reconstruct); "+
+			"this is NOT a faithful re-run of the original request sequence.\n", paramsMismatch, len(entries))
+	}
+	for _, method := range order {
+		b.WriteString(recorders[method].Report(fmt.Sprintf("%s_%s", cfg.TargetURL, method), detail))
+	}
+	return b.String(), nil
+}
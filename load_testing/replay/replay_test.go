@@ -0,0 +1,129 @@
+package replay
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+
+	"rpc-test/load_testing"
+)
+
+func writeLog(t *testing.T, lines []string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	return path
+}
+
+func TestRunReportFromLog(t *testing.T) {
+	path := writeLog(t, []string{
+		`{"ts_iso8601":"2026-01-01T00:00:00Z","url":"http://a","method":"getSlot","http_status":200,"total_ms":10}`,
+		`{"ts_iso8601":"2026-01-01T00:00:01Z","url":"http://a","method":"getSlot","http_status":200,"total_ms":20}`,
+		`{"ts_iso8601":"2026-01-01T00:00:02Z","url":"http://a","method":"getSlot","http_status":500,"total_ms":0}`,
+	})
+
+	report, err := Run(Config{LogPath: path, Detail: load_testing.DetailShort})
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if !strings.Contains(report, "getSlot") {
+		t.Fatalf("report = %q, want it to mention method getSlot", report)
+	}
+	if !strings.Contains(report, "requests=2 errors=1") {
+		t.Fatalf("report = %q, want requests=2 errors=1", report)
+	}
+}
+
+func TestRunReportFromLogCountsValidationErrors(t *testing.T) {
+	path := writeLog(t, []string{
+		`{"ts_iso8601":"2026-01-01T00:00:00Z","url":"http://a","method":"getBalance","http_status":200,"total_ms":10}`,
+		`{"ts_iso8601":"2026-01-01T00:00:01Z","url":"http://a","method":"getBalance","http_status":200,"total_ms":20,"validation_error":"missing expected field"}`,
+	})
+
+	report, err := Run(Config{LogPath: path, Detail: load_testing.DetailShort})
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if !strings.Contains(report, "requests=1 errors=1") {
+		t.Fatalf("report = %q, want requests=1 errors=1 (a clean 200 that still failed Validate)", report)
+	}
+}
+
+func TestRunNoEntries(t *testing.T) {
+	path := writeLog(t, nil)
+	if _, err := Run(Config{LogPath: path}); err == nil {
+		t.Fatal("Run() with an empty log = nil error, want an error")
+	}
+}
+
+func TestRerunWarnsOnParamsMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"value":1}}`))
+	}))
+	defer server.Close()
+
+	// getSlot's params never depend on fixtures, so replay reconstructs them
+	// exactly and its hash should match.
+	getSlotMethod := load_testing.Methods[load_testing.GetSlot]
+	getSlotBody, err := json.Marshal(jsonrpc.NewRequest(load_testing.GetSlot, getSlotMethod.BuildParams(0, &load_testing.DefaultFixtures)))
+	if err != nil {
+		t.Fatalf("marshal getSlot request: %s", err)
+	}
+
+	path := writeLog(t, []string{
+		`{"ts_iso8601":"2026-01-01T00:00:00Z","url":"` + server.URL + `","method":"getSlot","params_hash":"` + load_testing.ParamsHash(getSlotBody) + `","http_status":200,"total_ms":10}`,
+		// getAccountInfo's params depend on Fixtures.AccountKeys, which the
+		// original run supplied via a -scenario file replay doesn't have
+		// access to, so its logged hash won't match what rerun rebuilds.
+		`{"ts_iso8601":"2026-01-01T00:00:01Z","url":"` + server.URL + `","method":"getAccountInfo","params_hash":"deadbeef","http_status":200,"total_ms":10}`,
+	})
+
+	report, err := Run(Config{LogPath: path, TargetURL: server.URL, Detail: load_testing.DetailShort})
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if !strings.Contains(report, "WARNING") || !strings.Contains(report, "1/2 replayed requests") {
+		t.Fatalf("report = %q, want a warning that 1/2 requests didn't match their logged params hash", report)
+	}
+}
+
+func TestRerunNoWarningWhenParamsMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"value":1}}`))
+	}))
+	defer server.Close()
+
+	getSlotMethod := load_testing.Methods[load_testing.GetSlot]
+	getSlotBody, err := json.Marshal(jsonrpc.NewRequest(load_testing.GetSlot, getSlotMethod.BuildParams(0, &load_testing.DefaultFixtures)))
+	if err != nil {
+		t.Fatalf("marshal getSlot request: %s", err)
+	}
+
+	path := writeLog(t, []string{
+		`{"ts_iso8601":"2026-01-01T00:00:00Z","url":"` + server.URL + `","method":"getSlot","params_hash":"` + load_testing.ParamsHash(getSlotBody) + `","http_status":200,"total_ms":10}`,
+	})
+
+	report, err := Run(Config{LogPath: path, TargetURL: server.URL, Detail: load_testing.DetailShort})
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if strings.Contains(report, "WARNING") {
+		t.Fatalf("report = %q, want no mismatch warning when rebuilt params match the log", report)
+	}
+}
+
+func TestRunMissingFile(t *testing.T) {
+	if _, err := Run(Config{LogPath: filepath.Join(t.TempDir(), "missing.ndjson")}); err == nil {
+		t.Fatal("Run() with a missing file = nil error, want an error")
+	}
+}
@@ -0,0 +1,140 @@
+package load_testing
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// RequestLogEntry is one line of a -requestLog NDJSON file: a structured,
+// machine-readable record of a single request, rich enough to diff results
+// across runs or replay the request sequence against a different provider
+// without re-deriving it from the unstructured test_competitors.log output.
+type RequestLogEntry struct {
+	Timestamp    string `json:"ts_iso8601"`
+	URL          string `json:"url"`
+	Method       string `json:"method"`
+	ParamsHash   string `json:"params_hash"`
+	HTTPStatus   int    `json:"http_status"`
+	RPCErrorCode int    `json:"rpc_error_code,omitempty"`
+	// ValidationError is set when the response was a clean HTTP 200 with no
+	// RPC error but method.Validate rejected it, so replay's error detection
+	// (which otherwise only looks at HTTPStatus/RPCErrorCode) doesn't
+	// mistake the entry for a success.
+	ValidationError string  `json:"validation_error,omitempty"`
+	TotalMs         float64 `json:"total_ms"`
+	DNSMs           float64 `json:"dns_ms"`
+	TCPMs           float64 `json:"tcp_ms"`
+	TLSMs           float64 `json:"tls_ms"`
+	TTFBMs          float64 `json:"ttfb_ms"`
+	BytesIn         int     `json:"bytes_in"`
+	BytesOut        int     `json:"bytes_out"`
+	Attempt         uint    `json:"attempt"`
+}
+
+// RequestLog streams RequestLogEntry records to an NDJSON file as a run
+// progresses. A nil *RequestLog is valid and a no-op, so callers on the hot
+// path don't need to guard every call with a -requestLog-was-set check.
+type RequestLog struct {
+	sampleRate float64
+	rng        *rand.Rand
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRequestLog opens path and returns a *RequestLog that writes one NDJSON
+// line per logged request, sampled at sampleRate (1 logs every request, 0.1
+// logs roughly one in ten). If path is empty it returns (nil, nil) and every
+// method becomes a no-op.
+func NewRequestLog(path string, sampleRate float64) (*RequestLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o666) //nolint:revive
+	if err != nil {
+		return nil, fmt.Errorf("open request log: %w", err)
+	}
+
+	return &RequestLog{
+		sampleRate: sampleRate,
+		rng:        rand.New(rand.NewSource(1)), //nolint:gosec
+		file:       file,
+		enc:        json.NewEncoder(file),
+	}, nil
+}
+
+// Log records one request/outcome pair, subject to sampleRate. attempt is
+// the zero-based repeat index within benchmarkMethod's reqPerMethod loop.
+func (l *RequestLog) Log(url, method string, request []byte, outcome RequestOutcome, attempt uint) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	skip := l.sampleRate < 1 && l.rng.Float64() >= l.sampleRate
+	l.mu.Unlock()
+	if skip {
+		return
+	}
+
+	entry := RequestLogEntry{
+		Timestamp:       time.Now().UTC().Format(time.RFC3339Nano),
+		URL:             url,
+		Method:          method,
+		ParamsHash:      ParamsHash(request),
+		HTTPStatus:      outcome.StatusCode,
+		RPCErrorCode:    outcome.RPCErrorCode,
+		ValidationError: validationErrorString(outcome.ValidationErr),
+		TotalMs:         float64(outcome.Duration.Microseconds()) / 1000,
+		DNSMs:           float64(outcome.Timings.DNSLookup.Microseconds()) / 1000,
+		TCPMs:           float64(outcome.Timings.TCPConnection.Microseconds()) / 1000,
+		TLSMs:           float64(outcome.Timings.TLSHandshake.Microseconds()) / 1000,
+		TTFBMs:          float64((outcome.Timings.DNSLookup + outcome.Timings.TCPConnection + outcome.Timings.TLSHandshake + outcome.Timings.ServerProcessing).Microseconds()) / 1000,
+		BytesIn:         outcome.BytesIn,
+		BytesOut:        outcome.BytesOut,
+		Attempt:         attempt,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.enc.Encode(entry)
+}
+
+// validationErrorString renders a method.Validate failure for
+// RequestLogEntry.ValidationError, or "" when err is nil so the field is
+// omitted from the NDJSON line.
+func validationErrorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Close flushes and closes the underlying file. Safe to call on a nil
+// *RequestLog.
+func (l *RequestLog) Close() {
+	if l == nil {
+		return
+	}
+	_ = l.file.Close()
+}
+
+// ParamsHash hashes the raw JSON-RPC request body so identical requests
+// (same method, same params) compare equal across runs and providers
+// without needing to re-parse params at replay time. It's exported so
+// load_testing/replay can tell whether the params it rebuilds for a replayed
+// request actually match the params the original run logged.
+func ParamsHash(request []byte) string {
+	sum := sha256.Sum256(request)
+	return fmt.Sprintf("%x", sum)
+}
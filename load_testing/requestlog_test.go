@@ -0,0 +1,138 @@
+package load_testing
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewRequestLogEmptyPathIsNoop(t *testing.T) {
+	l, err := NewRequestLog("", 1)
+	if err != nil {
+		t.Fatalf("NewRequestLog: %s", err)
+	}
+	if l != nil {
+		t.Fatalf("NewRequestLog(\"\", ...) = %v, want nil", l)
+	}
+	// Log and Close must tolerate a nil *RequestLog.
+	l.Log("url", "method", nil, RequestOutcome{}, 0)
+	l.Close()
+}
+
+func TestRequestLogWritesEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	l, err := NewRequestLog(path, 1)
+	if err != nil {
+		t.Fatalf("NewRequestLog: %s", err)
+	}
+
+	outcome := RequestOutcome{
+		Duration:   15 * time.Millisecond,
+		Timings:    RequestTimings{DNSLookup: time.Millisecond, TCPConnection: 2 * time.Millisecond},
+		StatusCode: 200,
+		BytesIn:    42,
+	}
+	l.Log("http://example.test", "getAccountInfo", []byte(`{"method":"getAccountInfo"}`), outcome, 3)
+	l.Close()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.URL != "http://example.test" || got.Method != "getAccountInfo" {
+		t.Fatalf("entry = %+v, want url/method to match", got)
+	}
+	if got.HTTPStatus != 200 || got.BytesIn != 42 || got.Attempt != 3 {
+		t.Fatalf("entry = %+v, want status=200 bytes_in=42 attempt=3", got)
+	}
+	if got.TotalMs != 15 {
+		t.Fatalf("entry.TotalMs = %v, want 15", got.TotalMs)
+	}
+	if got.ParamsHash == "" {
+		t.Fatal("entry.ParamsHash is empty")
+	}
+}
+
+func TestRequestLogWritesValidationError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	l, err := NewRequestLog(path, 1)
+	if err != nil {
+		t.Fatalf("NewRequestLog: %s", err)
+	}
+
+	outcome := RequestOutcome{
+		StatusCode:    200,
+		ValidationErr: errors.New("missing expected field"),
+	}
+	l.Log("http://example.test", "getBalance", []byte(`{"method":"getBalance"}`), outcome, 0)
+	l.Close()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.HTTPStatus != 200 || got.RPCErrorCode != 0 {
+		t.Fatalf("entry = %+v, want the underlying 200/0 from the round trip", got)
+	}
+	if got.ValidationError != "missing expected field" {
+		t.Fatalf("entry.ValidationError = %q, want %q", got.ValidationError, "missing expected field")
+	}
+}
+
+func TestRequestLogSampling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	l, err := NewRequestLog(path, 0.5)
+	if err != nil {
+		t.Fatalf("NewRequestLog: %s", err)
+	}
+	for i := 0; i < 1000; i++ {
+		l.Log("url", "method", nil, RequestOutcome{}, uint(i))
+	}
+	l.Close()
+
+	entries := readEntries(t, path)
+	if len(entries) == 0 || len(entries) == 1000 {
+		t.Fatalf("got %d logged entries out of 1000 at sampleRate 0.5, want somewhere strictly in between", len(entries))
+	}
+}
+
+func TestParamsHashStable(t *testing.T) {
+	a := ParamsHash([]byte(`{"method":"getSlot"}`))
+	b := ParamsHash([]byte(`{"method":"getSlot"}`))
+	c := ParamsHash([]byte(`{"method":"getBalance"}`))
+	if a != b {
+		t.Fatalf("paramsHash not stable for identical input: %s != %s", a, b)
+	}
+	if a == c {
+		t.Fatalf("paramsHash collided for different input")
+	}
+}
+
+func readEntries(t *testing.T, path string) []RequestLogEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %s", path, err)
+	}
+	defer file.Close()
+
+	var entries []RequestLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry RequestLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("parse entry: %s", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan %s: %s", path, err)
+	}
+	return entries
+}
@@ -0,0 +1,132 @@
+package load_testing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildPlanWeighting(t *testing.T) {
+	scenario := &Scenario{
+		Methods: []ScenarioEntry{
+			{Method: GetAccountInfo, Weight: 2},
+			{Method: GetBalance, Weight: 1},
+		},
+	}
+
+	plan := buildPlan(scenario)
+
+	var accountInfo, balance int
+	for _, method := range plan {
+		switch method {
+		case GetAccountInfo:
+			accountInfo++
+		case GetBalance:
+			balance++
+		default:
+			t.Fatalf("unexpected method %q in plan", method)
+		}
+	}
+	if accountInfo != 2 || balance != 1 {
+		t.Fatalf("plan = %v, want 2 getAccountInfo and 1 getBalance entries", plan)
+	}
+}
+
+func TestBuildPlanDefaultsZeroWeightToOne(t *testing.T) {
+	scenario := &Scenario{
+		Methods: []ScenarioEntry{{Method: GetSlot, Weight: 0}},
+	}
+	if plan := buildPlan(scenario); len(plan) != 1 {
+		t.Fatalf("buildPlan() = %v, want a single entry", plan)
+	}
+}
+
+func TestReqPerMethodFor(t *testing.T) {
+	scenario := &Scenario{
+		Methods: []ScenarioEntry{
+			{Method: GetAccountInfo, ReqPerMethod: 5},
+			{Method: GetBalance},
+		},
+	}
+
+	if got := reqPerMethodFor(scenario, GetAccountInfo, 1); got != 5 {
+		t.Fatalf("reqPerMethodFor(override) = %d, want 5", got)
+	}
+	if got := reqPerMethodFor(scenario, GetBalance, 1); got != 1 {
+		t.Fatalf("reqPerMethodFor(no override) = %d, want fallback 1", got)
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.json")
+	const body = `{
+		"fixtures": {"accountKeys": ["11111111111111111111111111111111"]},
+		"methods": [{"method": "getAccountInfo", "weight": 1}]
+	}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		t.Fatalf("LoadScenario: %s", err)
+	}
+	if len(scenario.Methods) != 1 || scenario.Methods[0].Method != GetAccountInfo {
+		t.Fatalf("scenario.Methods = %+v, want a single getAccountInfo entry", scenario.Methods)
+	}
+}
+
+func TestLoadScenarioYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.yml")
+	const body = `
+fixtures:
+  accountKeys:
+    - "11111111111111111111111111111111"
+methods:
+  - method: getAccountInfo
+    weight: 2
+  - method: getBalance
+    weight: 1
+`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		t.Fatalf("LoadScenario: %s", err)
+	}
+	if len(scenario.Methods) != 2 || scenario.Methods[0].Method != GetAccountInfo || scenario.Methods[0].Weight != 2 {
+		t.Fatalf("scenario.Methods = %+v, want getAccountInfo(weight=2) then getBalance", scenario.Methods)
+	}
+	if len(scenario.Fixtures.AccountKeys) != 1 {
+		t.Fatalf("scenario.Fixtures.AccountKeys = %v, want one entry", scenario.Fixtures.AccountKeys)
+	}
+}
+
+func TestLoadScenarioRejectsUnknownMethod(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.json")
+	const body = `{"methods": [{"method": "notARealMethod"}]}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	if _, err := LoadScenario(path); err == nil {
+		t.Fatal("LoadScenario() with unknown method = nil error, want an error")
+	}
+}
+
+func TestLoadScenarioRejectsEmptyMethods(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.json")
+	if err := os.WriteFile(path, []byte(`{"methods": []}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	if _, err := LoadScenario(path); err == nil {
+		t.Fatal("LoadScenario() with no methods = nil error, want an error")
+	}
+}
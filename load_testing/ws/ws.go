@@ -0,0 +1,277 @@
+// Package ws benchmarks the latency of Solana's websocket pubsub API
+// (accountSubscribe, slotSubscribe, logsSubscribe, signatureSubscribe),
+// which the HTTP load_testing package can't exercise.
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+// Channel names accepted in Config.Channels.
+const (
+	AccountSubscribe   = "accountSubscribe"
+	SlotSubscribe      = "slotSubscribe"
+	LogsSubscribe      = "logsSubscribe"
+	SignatureSubscribe = "signatureSubscribe"
+)
+
+// Config describes one websocket benchmark run.
+type Config struct {
+	URL           string
+	Connections   uint
+	Channels      []string
+	AccountKeys   []string
+	SignatureKeys []string
+	Duration      time.Duration
+}
+
+// ChannelStats accumulates latency and loss signals for one subscribed
+// channel, merged across every connection that subscribed to it.
+type ChannelStats struct {
+	Channel string
+
+	notificationCount uint64 // atomic
+	droppedCount      uint64 // atomic
+
+	mu                       sync.Mutex
+	firstNotificationLatency []time.Duration
+	interNotificationJitter  []time.Duration
+}
+
+func newChannelStats(channel string) *ChannelStats {
+	return &ChannelStats{Channel: channel}
+}
+
+func (s *ChannelStats) recordFirst(d time.Duration) {
+	s.mu.Lock()
+	s.firstNotificationLatency = append(s.firstNotificationLatency, d)
+	s.mu.Unlock()
+}
+
+func (s *ChannelStats) recordJitter(d time.Duration) {
+	s.mu.Lock()
+	s.interNotificationJitter = append(s.interNotificationJitter, d)
+	s.mu.Unlock()
+}
+
+func (s *ChannelStats) recordNotification() {
+	atomic.AddUint64(&s.notificationCount, 1)
+}
+
+func (s *ChannelStats) recordDropped(n uint64) {
+	atomic.AddUint64(&s.droppedCount, n)
+}
+
+// NotificationCount is the number of notifications received across every
+// connection subscribed to this channel.
+func (s *ChannelStats) NotificationCount() uint64 {
+	return atomic.LoadUint64(&s.notificationCount)
+}
+
+// DroppedCount is the number of notifications this channel is believed to
+// have missed, detected via gaps in a monotonically increasing sequence
+// (only meaningful for slotSubscribe today).
+func (s *ChannelStats) DroppedCount() uint64 {
+	return atomic.LoadUint64(&s.droppedCount)
+}
+
+func mean(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range samples {
+		sum += d
+	}
+	return sum / time.Duration(len(samples))
+}
+
+// Report summarizes first-notification latency and inter-notification
+// jitter for this channel.
+func (s *ChannelStats) Report() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("channel=%s notifications=%d dropped=%d firstNotificationLatency(avg)=%s interNotificationJitter(avg)=%s",
+		s.Channel, s.NotificationCount(), s.DroppedCount(), mean(s.firstNotificationLatency), mean(s.interNotificationJitter))
+}
+
+type subscribeRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params,omitempty"`
+}
+
+type subscribeResponse struct {
+	ID     int    `json:"id"`
+	Result uint64 `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type notification struct {
+	Method string `json:"method"`
+	Params struct {
+		Subscription uint64          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+func buildParams(channel string, accountKeys, signatureKeys []string, iter int) []interface{} {
+	switch channel {
+	case AccountSubscribe:
+		if len(accountKeys) == 0 {
+			return nil
+		}
+		return []interface{}{accountKeys[iter%len(accountKeys)], map[string]interface{}{"encoding": "jsonParsed", "commitment": "finalized"}}
+	case LogsSubscribe:
+		return []interface{}{"all", map[string]interface{}{"commitment": "finalized"}}
+	case SignatureSubscribe:
+		if len(signatureKeys) == 0 {
+			return nil
+		}
+		return []interface{}{signatureKeys[iter%len(signatureKeys)], map[string]interface{}{"commitment": "finalized"}}
+	case SlotSubscribe:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// StartTest opens cfg.Connections concurrent websocket connections to
+// cfg.URL, subscribes each of them to every channel in cfg.Channels, and
+// records notification latency until cfg.Duration elapses. It returns
+// merged stats keyed by channel name.
+func StartTest(cfg Config) map[string]*ChannelStats {
+	stats := make(map[string]*ChannelStats, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		stats[channel] = newChannelStats(channel)
+	}
+
+	connections := cfg.Connections
+	if connections == 0 {
+		connections = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := uint(0); i < connections; i++ {
+		wg.Add(1)
+		go func(iter int) {
+			defer wg.Done()
+			runConnection(cfg, iter, stats)
+		}(int(i))
+	}
+	wg.Wait()
+
+	return stats
+}
+
+func runConnection(cfg Config, iter int, stats map[string]*ChannelStats) {
+	conn, _, err := websocket.DefaultDialer.Dial(cfg.URL, nil)
+	if err != nil {
+		log.Errorf("ws dial %s: %s", cfg.URL, err)
+		return
+	}
+	defer conn.Close()
+
+	subscriptionChannel := make(map[uint64]string, len(cfg.Channels))
+	subscribedAt := make(map[uint64]time.Time, len(cfg.Channels))
+
+	for id, channel := range cfg.Channels {
+		req := subscribeRequest{
+			JSONRPC: "2.0",
+			ID:      id + 1,
+			Method:  channel,
+			Params:  buildParams(channel, cfg.AccountKeys, cfg.SignatureKeys, iter),
+		}
+		if err := conn.WriteJSON(req); err != nil {
+			log.Errorf("ws subscribe %s: %s", channel, err)
+			continue
+		}
+
+		var resp subscribeResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			log.Errorf("ws subscribe confirmation %s: %s", channel, err)
+			continue
+		}
+		if resp.Error != nil {
+			log.Errorf("ws subscribe %s: rpc error %d: %s", channel, resp.Error.Code, resp.Error.Message)
+			continue
+		}
+		subscriptionChannel[resp.Result] = channel
+		subscribedAt[resp.Result] = time.Now()
+	}
+
+	deadline := time.Now().Add(cfg.Duration)
+	lastNotificationAt := make(map[uint64]time.Time, len(subscriptionChannel))
+	lastSlot := make(map[uint64]uint64, len(subscriptionChannel))
+	firstSeen := make(map[uint64]bool, len(subscriptionChannel))
+
+	for time.Now().Before(deadline) {
+		conn.SetReadDeadline(deadline)
+		var n notification
+		if err := conn.ReadJSON(&n); err != nil {
+			break
+		}
+
+		channel, ok := channelForNotification(n.Method, subscriptionChannel, n.Params.Subscription)
+		if !ok {
+			continue
+		}
+		s := stats[channel]
+		if s == nil {
+			continue
+		}
+		now := time.Now()
+
+		if !firstSeen[n.Params.Subscription] {
+			firstSeen[n.Params.Subscription] = true
+			s.recordFirst(now.Sub(subscribedAt[n.Params.Subscription]))
+		} else if last, ok := lastNotificationAt[n.Params.Subscription]; ok {
+			s.recordJitter(now.Sub(last))
+		}
+		lastNotificationAt[n.Params.Subscription] = now
+		s.recordNotification()
+
+		if channel == SlotSubscribe {
+			recordSlotGap(n.Params.Result, n.Params.Subscription, lastSlot, s)
+		}
+	}
+}
+
+func channelForNotification(method string, subscriptionChannel map[uint64]string, subscription uint64) (string, bool) {
+	if channel, ok := subscriptionChannel[subscription]; ok {
+		return channel, true
+	}
+	// fall back to the notification's own method name (e.g. "slotNotification")
+	for _, channel := range subscriptionChannel {
+		if method == strings.TrimSuffix(channel, "Subscribe")+"Notification" {
+			return channel, true
+		}
+	}
+	return "", false
+}
+
+func recordSlotGap(result json.RawMessage, subscription uint64, lastSlot map[uint64]uint64, s *ChannelStats) {
+	var slotInfo struct {
+		Slot uint64 `json:"slot"`
+	}
+	if err := json.Unmarshal(result, &slotInfo); err != nil {
+		return
+	}
+
+	if prev, ok := lastSlot[subscription]; ok && slotInfo.Slot > prev+1 {
+		s.recordDropped(slotInfo.Slot - prev - 1)
+	}
+	lastSlot[subscription] = slotInfo.Slot
+}
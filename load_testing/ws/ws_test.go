@@ -0,0 +1,79 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildParams(t *testing.T) {
+	accountKeys := []string{"acct1", "acct2"}
+	signatureKeys := []string{"sig1", "sig2"}
+
+	if got := buildParams(AccountSubscribe, accountKeys, signatureKeys, 1); len(got) != 2 || got[0] != "acct2" {
+		t.Fatalf("buildParams(accountSubscribe) = %v, want params keyed on acct2", got)
+	}
+	if got := buildParams(AccountSubscribe, nil, signatureKeys, 0); got != nil {
+		t.Fatalf("buildParams(accountSubscribe, no keys) = %v, want nil", got)
+	}
+
+	if got := buildParams(SignatureSubscribe, accountKeys, signatureKeys, 1); len(got) != 2 || got[0] != "sig2" {
+		t.Fatalf("buildParams(signatureSubscribe) = %v, want params keyed on sig2", got)
+	}
+	if got := buildParams(SignatureSubscribe, accountKeys, nil, 0); got != nil {
+		t.Fatalf("buildParams(signatureSubscribe, no keys) = %v, want nil", got)
+	}
+
+	if got := buildParams(LogsSubscribe, nil, nil, 0); len(got) != 2 {
+		t.Fatalf("buildParams(logsSubscribe) = %v, want 2 params", got)
+	}
+
+	if got := buildParams(SlotSubscribe, nil, nil, 0); got != nil {
+		t.Fatalf("buildParams(slotSubscribe) = %v, want nil", got)
+	}
+	if got := buildParams("unknownChannel", nil, nil, 0); got != nil {
+		t.Fatalf("buildParams(unknown) = %v, want nil", got)
+	}
+}
+
+func TestChannelForNotification(t *testing.T) {
+	subscriptionChannel := map[uint64]string{5: SlotSubscribe}
+
+	channel, ok := channelForNotification("slotNotification", subscriptionChannel, 5)
+	if !ok || channel != SlotSubscribe {
+		t.Fatalf("channelForNotification(known subscription) = (%q, %v), want (%q, true)", channel, ok, SlotSubscribe)
+	}
+
+	channel, ok = channelForNotification("slotNotification", subscriptionChannel, 999)
+	if !ok || channel != SlotSubscribe {
+		t.Fatalf("channelForNotification(fallback by method name) = (%q, %v), want (%q, true)", channel, ok, SlotSubscribe)
+	}
+
+	if _, ok := channelForNotification("accountNotification", subscriptionChannel, 999); ok {
+		t.Fatal("channelForNotification(unmatched) = true, want false")
+	}
+}
+
+func TestRecordSlotGap(t *testing.T) {
+	s := newChannelStats(SlotSubscribe)
+	lastSlot := make(map[uint64]uint64)
+
+	recordSlotGap(json.RawMessage(`{"slot": 100}`), 1, lastSlot, s)
+	if s.DroppedCount() != 0 {
+		t.Fatalf("DroppedCount() after first slot = %d, want 0", s.DroppedCount())
+	}
+
+	recordSlotGap(json.RawMessage(`{"slot": 101}`), 1, lastSlot, s)
+	if s.DroppedCount() != 0 {
+		t.Fatalf("DroppedCount() after contiguous slot = %d, want 0", s.DroppedCount())
+	}
+
+	recordSlotGap(json.RawMessage(`{"slot": 105}`), 1, lastSlot, s)
+	if s.DroppedCount() != 3 {
+		t.Fatalf("DroppedCount() after gap = %d, want 3", s.DroppedCount())
+	}
+
+	recordSlotGap(json.RawMessage(`invalid`), 1, lastSlot, s)
+	if s.DroppedCount() != 3 {
+		t.Fatalf("DroppedCount() after invalid payload = %d, want unchanged 3", s.DroppedCount())
+	}
+}